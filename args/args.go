@@ -3,7 +3,10 @@ package args
 import (
     "errors"
     "fmt"
+    "io"
     "os"
+    "sort"
+    "strconv"
     "strings"
 )
 
@@ -28,103 +31,260 @@ const (
     ANSIBGMagenta ANSICode = "\033[45m"
     ANSIBGCyan ANSICode = "\033[46m"
     ANSIBGWhite ANSICode = "\033[47m"
+    ANSIBold ANSICode = "\033[1m"
+    ANSIDim ANSICode = "\033[2m"
+    ANSIItalic ANSICode = "\033[3m"
+    ANSIUnderline ANSICode = "\033[4m"
+    ANSIReset ANSICode = "\033[0m"
 )
 
 type Results struct {
     /// Stores flag values after parsing
     Flag map[string]bool
-    /// Stores option values after parsing
+    /// Stores option values after parsing. Map options store the last pair
+    /// given
     Option map[string]string
+    /// Stores the values of map options after parsing
+    Map map[string]map[string]string
+    /// Stores the values of secret options after parsing
+    Secret map[string]Secret
     /// Stores positional arguments after parsing
     Positional []string
     /// Stores the command after parsing
     Command string
+    /// Stores the command and its subcommands after parsing
+    CommandPath []string
+    /// Stores the flags, options, positional arguments, commands and `--` in
+    /// the order they were given
+    Events []Event
+
+    origins map[string]ValueOrigin
+    files map[string]*os.File
+    fileAccess map[string]FileAccess
+}
+
+type Choice struct {
+    /// Value stored in `Results` when the choice is selected
+    Value string `json:"value"`
+    /// Alternative values that select the choice
+    Aliases []string `json:"aliases,omitempty"`
+    /// Choice's description displayed by the `Help` function
+    Help string `json:"help,omitempty"`
+}
+
+type boolFlag struct {
+    Help string
+    ShortOnly bool
+    Hidden bool
+    Persistent bool
+    Var *bool
+    /// Value of the flag when it isn't given
+    DefaultsTo bool
+    /// The flag accepts a boolean value in every syntax, like `--name=false`
+    TakesValue bool
+}
+
+type positional struct {
+    Name string
+    Help string
+    Required bool
+    Variadic bool
+}
+
+type command struct {
+    Help string
+    Hidden bool
+    parser *Parser
 }
 
 type option struct {
     Help string
+    ShortOnly bool
+    Hidden bool
     DefaultsTo string
     Allowed []string
+    Validators []Validator
+    Choices []Choice
+    IgnoreCase bool
+    Required bool
+    Metavar string
+    Persistent bool
+    DefaultFunc func() (string, error)
+    DefaultShownAs *string
+    Map bool
+    Duplicates DuplicatePolicy
+    KeyValidators []Validator
+    Value Value
+    File bool
+    Access FileAccess
+    Secret bool
+    SecretOf string
 }
 
 type Parser struct {
-    flags map[string]string
-    flagsAbbr map[rune]string
+    flags map[string]boolFlag
+    flagsAbbr map[string]string
     options map[string]option
-    optionsAbbr map[rune]string
-    commands map[string]string
-    positional []string
+    optionsAbbr map[string]string
+    commands map[string]command
+    flagsOrder []string
+    optionsOrder []string
+    commandsOrder []string
+    env map[string]string
+    aliases map[string]string
+    commandAliases map[string]string
+    deprecated map[string]string
+    deprecatedCommands map[string]string
+    positional []positional
+    groups map[string]group
+    groupsOrder []string
+    argGroup map[string]string
+    parent *Parser
     name string
     description string
     cachedHelp string
 
-    /// Return an error if the first argument isn't a command. Ignored if no
-    /// commands have been added
+    /// Return an error if the first argument that isn't a flag or option isn't
+    /// a command. Ignored if no commands have been added
     CommandRequired bool
+    /// Header displayed by the `Help` function before the usage line
+    UsageHelpMsg string
     /// Header displayed by the `Help` function before the command descriptions
     CommandsHelpMsg string
     /// Header displayed by the `Help` function before the flag descriptions
     FlagsHelpMsg string
     /// Header displayed by the `Help` function before the option descriptions
     OptionsHelpMsg string
-    /// Color the output of the `Help` function
-    Colors bool
-    /// Color of the title outputed by the `Help` function
-    TitleColor ANSICode
-    /// Color of the description outputed by the `Help` function
-    DescriptionColor ANSICode
-    /// Color of the headers outputed by the `Help` function
-    HeaderColor ANSICode
-    /// Color of the command names outputed by the `Help` function
-    CommandColor ANSICode
-    /// Color of the command's description outputed by the `Help` function
-    CommandDescriptionColor ANSICode
-    /// Color of the flag names outputed by the `Help` function
-    FlagColor ANSICode
-    /// Color of the flag's description outputed by the `Help` function
-    FlagDescriptionColor ANSICode
-    /// Color of the option names outputed by the `Help` function
-    OptionColor ANSICode
-    /// Color of the option's description outputed by the `Help` function
-    OptionDescriptionColor ANSICode
-    /// Color of the option's allowed values outputed by the `Help` function
-    OptionAllowedColor ANSICode
+    /// Header displayed by the `Help` function before the descriptions of the
+    /// persistent flags and options
+    GlobalOptionsHelpMsg string
+    /// Where warnings about deprecated arguments are written. Ignored if
+    /// `OnWarning` is set
+    Warnings io.Writer
+    /// Called with each warning about deprecated arguments instead of writing
+    /// it to `Warnings`
+    OnWarning func(warning string)
+    /// Called to ask for the value of a required secret option that wasn't
+    /// given. If nil, the value is asked on the terminal without echoing it,
+    /// if stdin is a terminal
+    Prompt func(name string) (string, error)
+    /// Maximum width of the lines outputed by the `Help` function. The width
+    /// of the terminal is used if 0. Commands use the width of the top level
+    /// parser
+    Width int
+    /// Display the names and descriptions of the `Help` function side by
+    /// side. Commands use the layout of the top level parser
+    TwoColumns bool
+    /// When to color the output of the `Help` function and warnings. Commands
+    /// use the color mode of the top level parser
+    Colors ColorMode
+    /// Colors and styles of the output. Commands use the theme of the top
+    /// level parser
+    Theme Theme
+    /// Syntax of the flags and options on the command line and in the output
+    /// of the `Help` function. Commands use the syntax of the top level parser
+    Syntax Syntax
+}
+
+func (ap *Parser) getFlagsAbbr() map[string][]string {
+    return invertAliases(ap.flagsAbbr)
+}
+
+func (ap *Parser) getOptionsAbbr() map[string][]string {
+    return invertAliases(ap.optionsAbbr)
 }
 
-func (ap *Parser) getFlagsAbbr() map[string]rune {
-    abbr := map[string]rune{}
-    for k, v := range ap.flagsAbbr {
-        abbr[v] = k
+// Returns the aliases of each flag, option or command, sorted
+func invertAliases(aliases map[string]string) map[string][]string {
+    inv := map[string][]string{}
+    for k, v := range aliases {
+        inv[v] = append(inv[v], k)
+    }
+    for _, v := range inv {
+        sort.Strings(v)
     }
 
-    return abbr
+    return inv
 }
 
-func (ap *Parser) getOptionsAbbr() map[string]rune {
-    abbr := map[string]rune{}
-    for k, v := range ap.optionsAbbr {
-        abbr[v] = k
+// Returns the names of a flag or option as typed on the command line. Long
+// names come first and deprecated aliases are left out
+func (ap *Parser) argNames(name string) []string {
+    abbr := ap.getOptionsAbbr()[name]
+    if ap.isFlag(name) {
+        abbr = ap.getFlagsAbbr()[name]
+    }
+    names := ap.argLongNames(name)
+    for _, a := range abbr {
+        names = append(names, ap.syntax().shortPrefix() + a)
     }
 
-    return abbr
+    return names
 }
 
-func (ap *Parser) isAllowedOptionValue(opt string, val string) bool {
+func (ap *Parser) resolveAlias(name string) string {
+    canonical, found := ap.aliases[name]
+    if found { return canonical }
+
+    return name
+}
+
+func (ap *Parser) resolveCommandAlias(name string) string {
+    canonical, found := ap.commandAliases[name]
+    if found { return canonical }
+
+    return name
+}
+
+func (ap *Parser) normalizeOptionValue(opt string, val string) (string, error) {
     op := ap.options[opt]
+    if len(op.Choices) != 0 {
+        found := false
+        candidates := []string{}
+        for _, c := range op.Choices {
+            names := append([]string{c.Value}, c.Aliases...)
+            for _, n := range names {
+                if n == val || (op.IgnoreCase && strings.EqualFold(n, val)) {
+                    val = c.Value
+                    found = true
+                    break
+                }
+            }
+            if found { break }
+            candidates = append(candidates, names...)
+        }
+        if !found {
+            return "", invalidValueError(opt, val, candidates, op.IgnoreCase)
+        }
+    }
     alLen := len(op.Allowed)
-    allowed := false
     if alLen != 0 {
+        allowed := false
         for i := 0; i < alLen; i++ {
-            if op.Allowed[i] == val {
+            if op.Allowed[i] == val || (op.IgnoreCase && strings.EqualFold(op.Allowed[i], val)) {
+                val = op.Allowed[i]
                 allowed = true
                 break
             }
         }
-    }else {
-        allowed = true
+        if !allowed {
+            return "", invalidValueError(opt, val, op.Allowed, op.IgnoreCase)
+        }
+    }
+    for _, v := range op.Validators {
+        err := v.Check(val)
+        if err != nil {
+            return "", errors.New(fmt.Sprintf("invalid value: %s -> %s (%s)", opt, val, err))
+        }
+    }
+    if op.File {
+        err := checkFile(val, op.Access)
+        if err != nil {
+            return "", errors.New(fmt.Sprintf("invalid value: %s -> %s (%s)", opt, val, err))
+        }
     }
 
-    return allowed
+    return val, nil
 }
 
 /// Initialize the struct
@@ -132,89 +292,210 @@ func (ap *Parser) Init(name string, description string) {
     if name != "" { ap.name = name }
     if description != "" { ap.description = description }
     ap.CommandRequired = false
+    ap.UsageHelpMsg = "USAGE"
     ap.CommandsHelpMsg = "COMMANDS"
     ap.FlagsHelpMsg = "FLAGS"
     ap.OptionsHelpMsg = "OPTIONS"
-    ap.flags = map[string]string{}
-    ap.flagsAbbr = map[rune]string{}
+    ap.GlobalOptionsHelpMsg = "GLOBAL OPTIONS"
+    ap.flags = map[string]boolFlag{}
+    ap.flagsAbbr = map[string]string{}
     ap.options = map[string]option{}
-    ap.optionsAbbr = map[rune]string{}
-    ap.commands = map[string]string{}
-    ap.Colors = false
-    ap.TitleColor = ANSIGreen
-    ap.DescriptionColor = ANSIWhite
-    ap.HeaderColor = ANSIRed
-    ap.CommandColor = ANSIMagenta
-    ap.CommandDescriptionColor = ANSIWhite
-    ap.FlagColor = ANSIBlue
-    ap.FlagDescriptionColor = ANSIWhite
-    ap.OptionColor = ANSIBlue
-    ap.OptionDescriptionColor = ANSIWhite
-    ap.OptionAllowedColor = ANSIYellow
+    ap.optionsAbbr = map[string]string{}
+    ap.commands = map[string]command{}
+    ap.aliases = map[string]string{}
+    ap.commandAliases = map[string]string{}
+    ap.deprecated = map[string]string{}
+    ap.deprecatedCommands = map[string]string{}
+    ap.flagsOrder = []string{}
+    ap.optionsOrder = []string{}
+    ap.commandsOrder = []string{}
+    ap.env = map[string]string{}
+    ap.groups = map[string]group{}
+    ap.groupsOrder = []string{}
+    ap.argGroup = map[string]string{}
+    ap.Warnings = os.Stderr
+    ap.OnWarning = nil
+    ap.Prompt = nil
+    ap.Width = 0
+    ap.TwoColumns = false
+    ap.Colors = ColorsAuto
+    ap.Theme = DefaultTheme
+    ap.Syntax = SyntaxGNU
 }
 
 /// Add a flag
 /// @param name flag's name
 /// @param help flag's description
-/// @param abbr flag's abbreviation
+/// @param abbr flag's abbreviation. '\000' for none
 /// @return Error is the  flag already exists
 func (ap *Parser) AddFlag(name string, help string, abbr rune) error {
-    _, foundFl := ap.flags[name]
-    _, foundOp := ap.options[name]
-    if !foundFl && !foundOp {
-        ap.flags[name] = help
-        _, foundFlAbr := ap.flagsAbbr[abbr]
-        _, foundOpAbr := ap.optionsAbbr[abbr]
-        if !foundFlAbr && !foundOpAbr {
-            ap.flagsAbbr[abbr] = name
-        }else {
-            return errors.New(fmt.Sprintf("duplicate argument: %s", string(abbr)))
-        }
-    }else {
-        return errors.New(fmt.Sprintf("duplicate argument: %s", name))
-    }
-
-    return nil
+    return ap.Flag(Long(name), Help(help), Short(abbr))
 }
 
 /// Add an option
 /// @param name option's name
 /// @param help option's description
-/// @param abbr option's abbreviation
+/// @param abbr option's abbreviation. '\000' for none
 /// @param defaultsTo option's default value
 /// @param allowed option's allowed values. Doesn't necessarily need to contain the default value
 /// @return An error if the option already exists
 func (ap *Parser) AddOption(
     name string, help string, abbr rune, defaultsTo string, allowed []string,
 ) error {
-    _, foundOp := ap.options[name]
+    return ap.Option(
+        Long(name), Help(help), Short(abbr), Default(defaultsTo), Allowed(allowed...),
+    )
+}
+
+/// Add validators to an option. Validators run after the allowed values check
+/// @param name option's name
+/// @param validators validators to add
+/// @return An error if the option doesn't exist
+func (ap *Parser) AddValidator(name string, validators ...Validator) error {
+    op, found := ap.options[name]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: option %s does not exist", name))
+    }
+    op.Validators = append(op.Validators, validators...)
+    ap.options[name] = op
+
+    return nil
+}
+
+/// Set the values accepted by an option. Replaces the values set by previous
+/// calls
+/// @param name option's name
+/// @param choices option's accepted values
+/// @return An error if the option doesn't exist
+func (ap *Parser) SetChoices(name string, choices ...Choice) error {
+    op, found := ap.options[name]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: option %s does not exist", name))
+    }
+    op.Choices = choices
+    ap.options[name] = op
+
+    return nil
+}
+
+/// Match an option's allowed values and choices regardless of case
+/// @param name option's name
+/// @param ignoreCase whether to ignore case
+/// @return An error if the option doesn't exist
+func (ap *Parser) SetIgnoreCase(name string, ignoreCase bool) error {
+    op, found := ap.options[name]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: option %s does not exist", name))
+    }
+    op.IgnoreCase = ignoreCase
+    ap.options[name] = op
+
+    return nil
+}
+
+/// Return an error from `Parse` if the option isn't given on the command line
+/// or through its environment variable
+/// @param name option's name
+/// @param required whether the option is required
+/// @return An error if the option doesn't exist
+func (ap *Parser) SetRequired(name string, required bool) error {
+    op, found := ap.options[name]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: option %s does not exist", name))
+    }
+    op.Required = required
+    ap.options[name] = op
+
+    return nil
+}
+
+/// Set the name of an option's value displayed in the usage line and the
+/// `Help` function
+/// @param name option's name
+/// @param metavar value's name, for example "FILE"
+/// @return An error if the option doesn't exist
+func (ap *Parser) SetMetavar(name string, metavar string) error {
+    op, found := ap.options[name]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: option %s does not exist", name))
+    }
+    op.Metavar = metavar
+    ap.options[name] = op
+
+    return nil
+}
+
+/// Add alternative long names to a flag or option. `Results` always uses
+/// the original name
+/// @param name flag's or option's name
+/// @param aliases alternative names
+/// @return An error if the flag or option doesn't exist or an alias is already
+/// in use
+func (ap *Parser) AddAlias(name string, aliases ...string) error {
     _, foundFl := ap.flags[name]
-    if !foundOp && !foundFl {
-        ap.options[name] = option{Help: help, DefaultsTo: defaultsTo, Allowed: allowed}
-        if abbr != '\000' {
-            _, foundOpAbr := ap.optionsAbbr[abbr]
-            _, foundFlAbr := ap.flagsAbbr[abbr]
-            if !foundOpAbr && !foundFlAbr {
-                ap.optionsAbbr[abbr] = name
-            }else {
-                return errors.New(fmt.Sprintf("duplicate argument: %s", string(abbr)))
-            }
+    _, foundOp := ap.options[name]
+    if !foundFl && !foundOp {
+        return errors.New(fmt.Sprintf("invalid argument: %s does not exist", name))
+    }
+    for _, a := range aliases {
+        _, foundFl := ap.flags[a]
+        _, foundOp := ap.options[a]
+        _, foundAl := ap.aliases[a]
+        if foundFl || foundOp || foundAl {
+            return errors.New(fmt.Sprintf("duplicate argument: %s", a))
         }
-    }else {
-        return errors.New(fmt.Sprintf("duplicate argument: %s", name))
+        ap.aliases[a] = name
     }
 
     return nil
 }
 
-/// Add a command
+/// Describe a positional argument. Positional arguments are stored in
+/// `Results.Positional` in the order they are added
+/// @param name argument's name
+/// @param help argument's description
+/// @param required return an error from `Parse` if the argument is missing
+/// @param variadic the argument accepts any number of values. Must be the last
+/// positional argument
+/// @return An error if the argument already exists, a required argument
+/// follows an optional one or an argument follows a variadic one
+func (ap *Parser) AddPositional(name string, help string, required bool, variadic bool) error {
+    for _, p := range ap.positional {
+        if p.Name == name {
+            return errors.New(fmt.Sprintf("duplicate argument: %s", name))
+        }
+    }
+    count := len(ap.positional)
+    if count != 0 {
+        last := ap.positional[count - 1]
+        if last.Variadic {
+            return errors.New(fmt.Sprintf("invalid argument: %s follows variadic argument %s", name, last.Name))
+        }
+        if required && !last.Required {
+            return errors.New(fmt.Sprintf("invalid argument: required argument %s follows optional argument %s", name, last.Name))
+        }
+    }
+    ap.positional = append(ap.positional, positional{
+        Name: name, Help: help, Required: required, Variadic: variadic,
+    })
+
+    return nil
+}
+
+/// Add a command. Its own arguments are added to the parser returned by
+/// `Command`
 /// @param name command's name
 /// @param help command's description
 /// @return Error if the command already exists
 func (ap *Parser) AddCommand(name string, help string) error {
     _, found := ap.commands[name]
-    if !found {
-        ap.commands[name] = help
+    _, foundAl := ap.commandAliases[name]
+    if !found && !foundAl {
+        sub := new(Parser)
+        sub.Init(strings.TrimSpace(ap.name + " " + name), help)
+        sub.parent = ap
+        ap.commands[name] = command{Help: help, parser: sub}
+        ap.commandsOrder = append(ap.commandsOrder, name)
     }else {
         return errors.New(fmt.Sprintf("duplicate argument: %s", name))
     }
@@ -222,399 +503,310 @@ func (ap *Parser) AddCommand(name string, help string) error {
     return nil
 }
 
-/// Display the help message
-func (ap *Parser) Help() {
-    if ap.name != "" {
-        if ap.Colors { fmt.Print(ap.TitleColor) }
-        fmt.Print(ap.name)
-        if ap.Colors { fmt.Print("\033[0m") }
-    }
-    if ap.description != "" {
-        if ap.Colors { fmt.Print(ap.DescriptionColor) }
-        fmt.Print(" - ")
-        var indent string
-        nameLen := len(ap.name)
-        for i := 0; i < nameLen + 3; i++ {
-            indent += " "
-        }
-        token := strings.IndexRune(ap.description, '\n')
-        if token != -1 {
-            last := 0
-            for token != -1 {
-                if last == 0 {
-                    fmt.Print(ap.description[last:token + 1])
-                }else {
-                    fmt.Printf("%s%s\n", indent, ap.description[last:token + 1])
-                }
-                last = token + 1
-                token = strings.IndexRune(ap.description[last:], '\n')
-            }
-            if last < len(ap.description) - 1 {
-                fmt.Printf("%s%s\n", indent, ap.description[last:])
-            }
-        }else {
-            fmt.Println(ap.description)
+/// Add alternative names to a command. `Results` always uses the original name
+/// @param name command's name
+/// @param aliases alternative names
+/// @return An error if the command doesn't exist or an alias is already in use
+func (ap *Parser) AddCommandAlias(name string, aliases ...string) error {
+    _, found := ap.commands[name]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: command %s does not exist", name))
+    }
+    for _, a := range aliases {
+        _, found := ap.commands[a]
+        _, foundAl := ap.commandAliases[a]
+        if found || foundAl {
+            return errors.New(fmt.Sprintf("duplicate argument: %s", a))
         }
-        if ap.Colors { fmt.Print("\033[0m") }
+        ap.commandAliases[a] = name
     }
-    fmt.Println()
 
-    if len(ap.commands) != 0 {
-        if ap.CommandsHelpMsg != "" {
-            if ap.Colors { fmt.Print(ap.HeaderColor) }
-            fmt.Printf("%s\n", ap.CommandsHelpMsg)
-            if ap.Colors { fmt.Print("\033[0m") }
+    return nil
+}
+
+/// Parse the command line arguments
+/// @return A "Results" struct with the argument values or a `*ParseError`
+func (ap *Parser) Parse() (*Results, error) {
+    results, err := ap.parse()
+    if err != nil {
+        parseErr, isParseErr := err.(*ParseError)
+        if !isParseErr {
+            parseErr = &ParseError{Message: err.Error(), Index: -1}
         }
-        for k, v := range ap.commands {
-            if ap.Colors { fmt.Print(ap.CommandColor) }
-            fmt.Printf("    %s\n", k)
-            if ap.Colors { fmt.Print("\033[0m") }
-            indent := "        "
-            if v != "" {
-                if ap.Colors { fmt.Print(ap.CommandDescriptionColor) }
-                token := strings.IndexRune(v, '\n')
-                if token != -1 {
-                    last := 0
-                    for token != -1 {
-                        fmt.Printf("%s%s\n", indent, v[last:token + 1])
-                        last = token + 1
-                        token = strings.IndexRune(v[last:], '\n')
-                    }
-                    if last < len(v) - 1 {
-                        fmt.Printf("%s%s\n", indent, v[last:])
-                    }
-                }else {
-                    fmt.Printf("%s%s\n", indent, v)
-                }
-                if ap.Colors { fmt.Print("\033[0m") }
-            }
-            fmt.Println()
+        if parseErr.Usage == "" {
+            parseErr.Usage = ap.Usage()
         }
+
+        return nil, parseErr
     }
 
-    if len(ap.flags) != 0 {
-        abbr := ap.getFlagsAbbr()
-        if ap.FlagsHelpMsg != "" {
-            if ap.Colors { fmt.Print(ap.HeaderColor) }
-            fmt.Printf("%s\n", ap.FlagsHelpMsg)
-            if ap.Colors { fmt.Print("\033[0m") }
+    return results, nil
+}
+
+// Sets the default values of the parser's own flags and options and the
+// values of their environment variables
+func (ap *Parser) initResults(results *Results) error {
+    for k, v := range ap.flags {
+        results.Flag[k] = v.DefaultsTo
+    }
+    for k, v := range ap.options {
+        results.Option[k] = v.DefaultsTo
+        if v.Map { results.Map[k] = map[string]string{} }
+        if v.File { results.fileAccess[k] = v.Access }
+        if v.Secret {
+            delete(results.Option, k)
+            results.Secret[k] = Secret(v.DefaultsTo)
         }
-        for k, v := range ap.flags {
-            if ap.Colors { fmt.Print(ap.FlagColor) }
-            fmt.Printf("    --%s", k)
-            tmp, found := abbr[k]
+    }
+
+    return ap.applyEnv(results)
+}
+
+func (ap *Parser) parse() (*Results, error) {
+    results := new(Results)
+    results.Flag = map[string]bool{}
+    results.Option = map[string]string{}
+    results.Map = map[string]map[string]string{}
+    results.fileAccess = map[string]FileAccess{}
+    results.Secret = map[string]Secret{}
+    results.origins = map[string]ValueOrigin{}
+    err := ap.initResults(results)
+    if err != nil {
+        return nil, err
+    }
+
+    args := os.Args[1:]
+    argsLen := len(args)
+    // Arguments shown by errors, without the values of secret options
+    shown := args
+    // `current` parses the arguments of the last command and `levels` holds
+    // the parsers whose required options are checked
+    current := ap
+    levels := []*Parser{ap}
+    checkCommand := len(ap.commands) != 0
+    terminated := false
+    tokens := NewTokenizer(args)
+    tokens.Syntax = ap.syntax()
+    tokens.IsShort = func(name string) bool { return current.isShortName(name) }
+    for tokens.Next() {
+        token := tokens.Token()
+        i := token.Index
+        if checkCommand && !terminated && token.Kind == TokenPositional {
+            checkCommand = false
+            cmd := current.resolveCommandAlias(token.Value)
+            _, found := current.commands[cmd]
             if found {
-                fmt.Printf(", -%c", tmp)
-            }
-            if ap.Colors { fmt.Print("\033[0m") }
-            fmt.Println()
-            indent := "        "
-            if v != "" {
-                if ap.Colors { fmt.Print(ap.FlagDescriptionColor) }
-                token := strings.IndexRune(v, '\n')
-                if token != -1 {
-                    last := 0
-                    for token != -1 {
-                        fmt.Printf("%s%s\n", indent, v[last:token + 1])
-                        last = token + 1
-                        token = strings.IndexRune(v[last:], '\n')
-                    }
-                    if last < len(v) - 1 {
-                        fmt.Printf("%s%s\n", indent, v[last:])
+                name := current.checkDeprecatedCommand(token.Value, cmd)
+                if results.Command == "" {
+                    results.Command = name
+                }
+                results.CommandPath = append(results.CommandPath, name)
+                results.addEvent(EventCommand, name, "", args, i)
+                sub := current.commands[name].parser
+                if sub.hasOwnArgs() {
+                    err = sub.initResults(results)
+                    if err != nil {
+                        return nil, err
                     }
-                }else {
-                    fmt.Printf("%s%s\n", indent, v)
+                    levels = append(levels, sub)
+                }
+                current = sub.inherit(current)
+                checkCommand = sub.hasOwnArgs() && len(sub.commands) != 0
+                continue
+            }else if current.CommandRequired {
+                return nil, &ParseError{
+                    Message: fmt.Sprintf("invalid argument: \"%s\" is not a command", token.Value),
+                    Usage: current.Usage(), Args: shown, Index: i, parser: current,
                 }
-                if ap.Colors { fmt.Print("\033[0m") }
             }
-            fmt.Println()
+        }
+
+        switch token.Kind {
+            case TokenTerminator:
+                terminated = true
+                results.addEvent(EventTerminator, "", "", args, i)
+            case TokenLong, TokenLongValue:
+                err = current.parseLong(results, args, tokens)
+            case TokenShort:
+                err = current.parseShort(results, args, tokens)
+            case TokenPositional:
+                results.Positional = append(results.Positional, token.Value)
+                results.addEvent(EventPositional, "", token.Value, args, i)
+        }
+        if tokens.valueRead && current.options[current.tokenName(token)].Secret {
+            shown = redactValue(shown, tokens)
+        }
+        if err != nil {
+            // Invalid values given as a separate argument are reported at the
+            // value
+            return nil, &ParseError{
+                Message: err.Error(), Usage: current.Usage(),
+                Args: shown, Index: tokens.Index(), Arg: current.tokenArg(token.Raw),
+                parser: current,
+            }
         }
     }
 
-    if len(ap.options) != 0 {
-        abbr := ap.getOptionsAbbr()
-        if ap.OptionsHelpMsg != "" {
-            if ap.Colors { fmt.Print(ap.HeaderColor) }
-            fmt.Printf("%s\n", ap.OptionsHelpMsg)
-            if ap.Colors { fmt.Print("\033[0m") }
+    for _, level := range levels {
+        err = level.applyDefaultFuncs(results)
+        if err == nil {
+            err = level.promptSecrets(results)
         }
-        for k, v := range ap.options {
-            if ap.Colors { fmt.Print(ap.OptionColor) }
-            fmt.Printf("    --%s", k)
-            tmp, found := abbr[k]
-            if found {
-                fmt.Printf(", -%c", tmp)
+        if err != nil {
+            return nil, &ParseError{
+                Message: err.Error(), Usage: level.Usage(), Args: shown, Index: -1, parser: level,
             }
-            if ap.Colors { fmt.Print("\033[0m") }
-            allowedLen := len(v.Allowed)
-            if allowedLen != 0 {
-                if ap.Colors { fmt.Print(ap.OptionAllowedColor) }
-                fmt.Print(" ")
-                for ii := 0; ii < allowedLen; ii++ {
-                    if ii != allowedLen - 1 {
-                        fmt.Printf("%s|", v.Allowed[ii])
-                    }else {
-                        fmt.Print(v.Allowed[ii])
-                    }
+        }
+    }
+    if checkCommand && current.CommandRequired && argsLen != 0 {
+        return nil, &ParseError{
+            Message: "missing argument: <command>", Usage: current.Usage(), Args: shown, Index: -1,
+            parser: current,
+        }
+    }
+    for _, level := range levels {
+        for _, k := range level.optionsOrder {
+            if level.options[k].Required && !results.IsSet(k) {
+                return nil, &ParseError{
+                    Message: fmt.Sprintf("missing argument: %s", level.displayName(k)),
+                    Usage: level.Usage(), Args: shown, Index: -1, Arg: k, parser: level,
                 }
-                if ap.Colors { fmt.Print("\033[0m") }
             }
-            fmt.Println()
-            indent := "        "
-            if v.Help != "" {
-                if ap.Colors { fmt.Print(ap.OptionDescriptionColor) }
-                token := strings.IndexRune(v.Help, '\n')
-                if token != -1 {
-                    last := 0
-                    for {
-                        fmt.Printf("%s%s", indent, v.Help[last:token + 1])
-                        last = token + 1
-                        token = strings.IndexRune(v.Help[last:], '\n')
-                        if token != -1 {
-                            token += last
-                        }else {
-                            break
-                        }
-                    }
-                    if last < len(v.Help) - 1 {
-                        fmt.Printf("%s%s\n", indent, v.Help[last:])
-                    }
-                }else {
-                    fmt.Printf("%s%s\n", indent, v.Help)
-                }
-                if ap.Colors { fmt.Print("\033[0m") }
+        }
+    }
+    for ii, p := range current.positional {
+        if p.Required && ii >= len(results.Positional) {
+            return nil, &ParseError{
+                Message: fmt.Sprintf("missing argument: %s", p.Name),
+                Usage: current.Usage(), Args: shown, Index: -1, Arg: p.Name, parser: current,
             }
-            fmt.Println()
         }
     }
+
+    return results, nil
 }
 
-/// Parse the command line arguments
-/// @return A "Results" struct with the argument values or error
-func (ap *Parser) Parse() (*Results, error) {
-    results := new(Results)
-    results.Flag = map[string]bool{}
-    results.Option = map[string]string{}
+// Parses the long argument of the current token
+func (ap *Parser) parseLong(results *Results, args []string, tokens *Tokenizer) error {
+    token := tokens.Token()
+    name := ap.resolveAlias(token.Name)
+    origin := argvOrigin(args, token.Index)
+    fl, found := ap.flags[name]
+    if found && !fl.ShortOnly {
+        val := true
+        if token.Kind == TokenLongValue {
+            var err error
+            val, err = ap.flagValue(name, token.Name, token.Value)
+            if err != nil {
+                return err
+            }
+        }
+        ap.setFlag(results, ap.checkDeprecated(ap.syntax().longPrefix() + token.Name, token.Name, name), val, origin)
+        return nil
+    }
+    op, found := ap.options[name]
+    if !found || op.ShortOnly {
+        return errors.New(fmt.Sprintf("invalid argument: %s", token.Name))
+    }
+    val, hasValue := tokens.Value()
+    if !hasValue {
+        return errors.New(fmt.Sprintf("missing value: %s", token.Name))
+    }
+    name = ap.checkDeprecated(ap.syntax().longPrefix() + token.Name, token.Name, name)
+
+    return ap.setOption(results, name, val, origin)
+}
 
-    for k := range ap.flags {
-        results.Flag[k] = false
+// Parses the short argument of the current token
+func (ap *Parser) parseShort(results *Results, args []string, tokens *Tokenizer) error {
+    token := tokens.Token()
+    origin := argvOrigin(args, token.Index)
+    attached := tokens.HasValue()
+    // Names longer than one character are only accepted by `IsShort` if they
+    // exist
+    long := len([]rune(token.Name)) > 1
+    fl, found := ap.flagsAbbr[token.Name]
+    if found && tokens.hasSeparator() {
+        text, _ := tokens.Value()
+        val, err := ap.flagValue(fl, token.Name, text)
+        if err != nil {
+            return err
+        }
+        ap.setFlag(results, ap.checkDeprecated(ap.syntax().shortPrefix() + token.Name, "", fl), val, origin)
+        return nil
     }
-    for k, v := range ap.options {
-        results.Option[k] = v.DefaultsTo
+    if found && !(long && attached) {
+        ap.setFlag(results, ap.checkDeprecated(ap.syntax().shortPrefix() + token.Name, "", fl), true, origin)
+        return nil
     }
+    op, found := ap.optionsAbbr[token.Name]
+    if !found {
+        if long || token.Raw == ap.syntax().shortPrefix() + token.Name {
+            return errors.New(fmt.Sprintf("invalid argument: %s", token.Name))
+        }
 
-    i := 0
-    skipCommandCheck := false
-    args := os.Args[1:]
-    argsLen := len(args)
-    for i < argsLen {
-        curArgLen := len(args[i])
-        if !skipCommandCheck && i == 0 && len(ap.commands) != 0 {
-            _, found := ap.commands[args[i]]
-            if found {
-                results.Command = args[i]
-                i++
-            }else {
-                if ap.CommandRequired {
-                    return nil, errors.New(
-                        fmt.Sprintf("invalid argument: \"%s\" is not a command", args[i]),
-                    )
-                }
-            }
-            skipCommandCheck = true
-        }else {
-            if args[i] == "--" {
-                for ii := i + 1; ii < argsLen; ii++ {
-                    results.Positional = append(results.Positional, args[ii])
-                }
-                i = argsLen
-            }else {
-                if curArgLen > 2 {
-                    if args[i][0] == '-' && args[i][1] != '-' {
-                        equals := strings.IndexRune(args[i], '=')
-                        if equals != -1 {
-                            if equals == 2 { // option
-                                op, found := ap.optionsAbbr[rune(args[i][1])]
-                                if found {
-                                    if curArgLen > 3 {
-                                        tmp := args[i][3:]
-                                        if ap.isAllowedOptionValue(op, tmp) {
-                                            results.Option[op] = tmp
-                                            i++
-                                        }else {
-                                            return nil, errors.New(
-                                                fmt.Sprintf("invalid value: %s -> %s", string(args[i][1]), tmp),
-                                            )
-                                        }
-                                    }else {
-                                        return nil, errors.New(
-                                            fmt.Sprintf("missing value: %s", string(args[i][1])),
-                                        )
-                                    }
-                                }
-                            }else { // multiple flags and one option
-                                for ii := 1; ii < equals - 1; ii++ {
-                                    fl, found := ap.flagsAbbr[rune(args[i][ii])]
-                                    if found {
-                                        results.Flag[fl] = true
-                                    }else {
-                                        return nil, errors.New(
-                                            fmt.Sprintf("invalid argument: flag %s does not exist", string(args[i][ii])),
-                                        )
-                                    }
-                                }
-                                if equals + 1 < curArgLen {
-                                    op, found := ap.optionsAbbr[rune(args[i][equals - 1])]
-                                    if found {
-                                        tmp := args[i][equals + 1:]
-                                        if ap.isAllowedOptionValue(op, tmp) {
-                                            results.Option[op] = tmp
-                                        }else {
-                                            return nil, errors.New(
-                                                fmt.Sprintf("missing value: %s", string(args[i][1])),
-                                            )
-                                        }
-                                    }
-                                }else {
-                                    return nil, errors.New(
-                                        fmt.Sprintf("missing value: %s", string(args[i][1])),
-                                    )
-                                }
-                                i++
-                            }
-                        }else { // option and value with no space
-                            op, found := ap.optionsAbbr[rune(args[i][1])]
-                            if found {
-                                tmp := args[i][2:]
-                                if ap.isAllowedOptionValue(op, tmp) {
-                                    results.Option[op] = tmp
-                                }else {
-                                    return nil, errors.New(
-                                        fmt.Sprintf("missing value: %s", string(args[i][1])),
-                                    )
-                                }
-                            }else { // multiple flags
-                                for ii := 1; ii < curArgLen; ii++ {
-                                    fl, found := ap.flagsAbbr[rune(args[i][ii])]
-                                    if found {
-                                        results.Flag[fl] = true
-                                    }else {
-                                        return nil, errors.New(
-                                            fmt.Sprintf("invalid argument: flag %s does not exist", string(args[i][ii])),
-                                        )
-                                    }
-                                }
-                            }
-                            i++
-                        }
-                    }else if args[i][0] == '-' && args[i][1] == '-' {
-                        equals := strings.IndexRune(args[i], '=')
-                        if equals != -1 {
-                            op := args[i][2:equals]
-                            var val string
-                            if equals + 1 < curArgLen {
-                                val = args[i][equals + 1:]
-                            }else {
-                                return nil, errors.New(
-                                    fmt.Sprintf("missing value: %s", op),
-                                )
-                            }
-                            if ap.isAllowedOptionValue(op, val) {
-                                results.Option[op] = val
-                            }else {
-                                return nil, errors.New(
-                                    fmt.Sprintf("invalid value: %s -> %s", op, val),
-                                )
-                            }
-                            i++
-                        }else {
-                            arg := args[i][2:]
-                            _, found := ap.flags[arg]
-                            if found {
-                                results.Flag[arg] = true
-                                i++
-                            }else {
-                                _, found := ap.options[arg]
-                                if found {
-                                    if i + 1 < argsLen {
-                                        if len(args[i + 1]) == 0 {
-                                            results.Option[arg] = args[i + 1]
-                                        }else {
-                                            if args[i + 1][0] != '-' {
-                                                if ap.isAllowedOptionValue(arg, args[i + 1]) {
-                                                    results.Option[arg] = args[i + 1]
-                                                }else {
-                                                    return nil, errors.New(
-                                                        fmt.Sprintf("invalid value: %s -> %s", arg, args[i + 1]),
-                                                    )
-                                                }
-                                            }else {
-                                                return nil, errors.New(
-                                                    fmt.Sprintf("missing value: %s", args[i][2:]),
-                                                )
-                                            }
-                                        }
-                                    }else {
-                                        return nil, errors.New(
-                                            fmt.Sprintf("missing value: %s", args[i][2:]),
-                                        )
-                                    }
-                                    i += 2
-                                }else {
-                                    return nil, errors.New(
-                                        fmt.Sprintf("invalid argument: %s", arg),
-                                    )
-                                }
-                            }
-                        }
-                    }else {
-                        results.Positional = append(results.Positional, args[i])
-                        i++
-                    }
-                }else if curArgLen == 2 {
-                    if args[i][0] == '-' {
-                        fl, found := ap.flagsAbbr[rune(args[i][1])]
-                        if found {
-                            results.Flag[fl] = true
-                            i++
-                        }else {
-                            op, found := ap.optionsAbbr[rune(args[i][1])]
-                            if found {
-                                if i + 1 < argsLen {
-                                    if len(args[i + 1]) == 0 {
-                                        results.Option[op] = args[i + 1]
-                                    }else {
-                                        if args[i + 1][0] != '-' {
-                                            results.Option[op] = args[i + 1]
-                                        }else {
-                                            return nil, errors.New(
-                                                fmt.Sprintf("missing value: %s", args[i]),
-                                            )
-                                        }
-                                    }
-                                }else {
-                                    return nil, errors.New(
-                                        fmt.Sprintf("missing value: %s", args[i]),
-                                    )
-                                }
-                                i += 2
-                            }else {
-                                return nil, errors.New(
-                                    fmt.Sprintf("invalid argument: %s", string(args[i][1])),
-                                )
-                            }
-                        }
-                    }else {
-                        results.Positional = append(results.Positional, args[i])
-                        i++
-                    }
-                }else {
-                    results.Positional = append(results.Positional, args[i])
-                    i++
-                }
-            }
+        return errors.New(fmt.Sprintf("invalid argument: flag %s does not exist", token.Name))
+    }
+    op = ap.checkDeprecated(ap.syntax().shortPrefix() + token.Name, "", op)
+    val, hasValue := tokens.Value()
+    if !hasValue {
+        if attached {
+            return errors.New(fmt.Sprintf("missing value: %s", token.Name))
         }
+
+        return errors.New(fmt.Sprintf("missing value: %s%s", ap.syntax().shortPrefix(), token.Name))
     }
 
-    return results, nil
+    return ap.setOption(results, op, val, origin)
+}
+
+func (ap *Parser) setOption(results *Results, name string, val string, origin ValueOrigin) error {
+    var err error
+    if ap.options[name].Secret || ap.options[name].SecretOf != "" {
+        return ap.setSecret(results, name, val, origin)
+    }else if ap.options[name].Map {
+        err = ap.setMapValue(results, name, val, origin)
+        val = results.Option[name]
+    }else {
+        val, err = ap.normalizeOptionValue(name, val)
+        if err == nil {
+            results.Option[name] = val
+            err = ap.setVar(name, val)
+        }
+    }
+    if err != nil {
+        return err
+    }
+    results.setOrigin(name, origin)
+    if origin.Source == SourceArgv {
+        results.Events = append(results.Events, Event{
+            Kind: EventOption, Name: name, Value: val, Index: origin.Index, Token: origin.Token,
+        })
+    }
+
+    return nil
+}
+
+// Parses the value given to the flag `name` typed as `typed`, such as
+// `-v=false`. Only `SyntaxSingleDash` and flags imported from a `flag.FlagSet`
+// accept values, as Go's `flag` package does
+func (ap *Parser) flagValue(name string, typed string, val string) (bool, error) {
+    if ap.syntax() != SyntaxSingleDash && !ap.flags[name].TakesValue {
+        return false, errors.New(fmt.Sprintf("invalid value: %s -> %s (flags don't take a value)", typed, val))
+    }
+    b, err := strconv.ParseBool(val)
+    if err != nil {
+        return false, errors.New(fmt.Sprintf("invalid value: %s -> %s (not a boolean)", typed, val))
+    }
+
+    return b, nil
+}
+
+func (ap *Parser) setFlag(results *Results, name string, val bool, origin ValueOrigin) {
+    results.Flag[name] = val
+    if ap.flags[name].Var != nil { *ap.flags[name].Var = val }
+    results.setOrigin(name, origin)
+    results.Events = append(results.Events, Event{
+        Kind: EventFlag, Name: name, Index: origin.Index, Token: origin.Token,
+    })
 }
@@ -91,4 +91,58 @@ func TestParseAll(t *testing.T) {
     if results.Command != "cmd02" { t.Error() }
     if results.Positional[0] != "uwu" { t.Error() }
     if results.Positional[1] != "owo" { t.Error() }
-}
\ No newline at end of file
+}
+
+func TestParseChoices(t *testing.T) {
+    os.Args = []string{"app.exe", "--op01", "YES", "--op02=Fast"}
+    var parser Parser
+    parser.Init("Test", "")
+    parser.AddOption("op01", "", '\000', "false", []string{})
+    parser.AddOption("op02", "", '\000', "", []string{"fast", "slow"})
+    parser.SetChoices(
+        "op01",
+        Choice{Value: "true", Aliases: []string{"y", "yes"}, Help: "Enable"},
+        Choice{Value: "false", Aliases: []string{"n", "no"}, Help: "Disable"},
+    )
+    parser.SetIgnoreCase("op01", true)
+    parser.SetIgnoreCase("op02", true)
+    results, err := parser.Parse()
+    if err != nil { t.Error(err) }
+    if results.Option["op01"] != "true" { t.Error() }
+    if results.Option["op02"] != "fast" { t.Error() }
+
+    os.Args = []string{"app.exe", "--op02", "fsat"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "invalid value: op02 -> fsat (did you mean fast?)" {
+        t.Error(err)
+    }
+    parser.SetIgnoreCase("op01", false)
+    os.Args = []string{"app.exe", "--op01", "YES"}
+    _, err = parser.Parse()
+    if err == nil { t.Error() }
+}
+
+func TestParseAliases(t *testing.T) {
+    os.Args = []string{"app.exe", "rm", "--colour", "--lvl=2", "--output", "out"}
+    var parser Parser
+    parser.Init("Test", "")
+    parser.AddFlag("color", "", 'c')
+    parser.AddOption("level", "", '\000', "", []string{})
+    parser.AddOption("out", "", 'o', "", []string{})
+    parser.AddCommand("remove", "")
+    if parser.AddAlias("color", "colour") != nil { t.Error() }
+    if parser.AddAlias("level", "lvl", "lv") != nil { t.Error() }
+    if parser.AddAlias("out", "output") != nil { t.Error() }
+    if parser.AddAlias("out", "color") == nil { t.Error() }
+    if parser.AddAlias("missing", "m") == nil { t.Error() }
+    if parser.AddCommandAlias("remove", "rm") != nil { t.Error() }
+    if parser.AddCommandAlias("remove", "rm") == nil { t.Error() }
+    if parser.AddFlag("colour", "", '\000') == nil { t.Error() }
+    results, err := parser.Parse()
+    if err != nil { t.Error(err) }
+    if results.Command != "remove" { t.Error() }
+    if !results.Flag["color"] { t.Error() }
+    if results.Option["level"] != "2" { t.Error() }
+    if results.Option["out"] != "out" { t.Error() }
+    if _, found := results.Flag["colour"]; found { t.Error() }
+}
@@ -0,0 +1,153 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+)
+
+/// Parser of a command's own flags, options, commands and positional
+/// arguments. Commands without arguments of their own accept all of their
+/// parent's arguments, the others only accept the parent's persistent ones
+/// @param name command's name or alias
+/// @return The command's parser or nil if the command doesn't exist
+func (ap *Parser) Command(name string) *Parser {
+    cmd, found := ap.commands[ap.resolveCommandAlias(name)]
+    if !found {
+        return nil
+    }
+
+    return cmd.parser
+}
+
+/// Make flags or options persistent. Persistent arguments are accepted
+/// before and after any command and listed by the `Help` function under
+/// `GlobalOptionsHelpMsg`
+/// @param names flags' or options' names
+/// @return An error if a flag or option doesn't exist
+func (ap *Parser) SetPersistent(names ...string) error {
+    for _, name := range names {
+        fl, foundFl := ap.flags[name]
+        op, foundOp := ap.options[name]
+        if foundFl {
+            fl.Persistent = true
+            ap.flags[name] = fl
+        }else if foundOp {
+            op.Persistent = true
+            ap.options[name] = op
+        }else {
+            return errors.New(fmt.Sprintf("invalid argument: %s does not exist", name))
+        }
+    }
+
+    return nil
+}
+
+func (ap *Parser) isPersistent(name string) bool {
+    return ap.flags[name].Persistent || ap.options[name].Persistent
+}
+
+// Reports whether the parser has flags, options, commands or positional
+// arguments of its own
+func (ap *Parser) hasOwnArgs() bool {
+    return len(ap.flags) != 0 || len(ap.options) != 0 || len(ap.commands) != 0 || len(ap.positional) != 0
+}
+
+// Parser at the top of the command tree
+func (ap *Parser) root() *Parser {
+    root := ap
+    for root.parent != nil {
+        root = root.parent
+    }
+
+    return root
+}
+
+// Parser used for the arguments following the command `ap` belongs to.
+// `view` is the parser used for the arguments preceding it
+func (ap *Parser) inherit(view *Parser) *Parser {
+    if !ap.hasOwnArgs() {
+        return view
+    }
+
+    merged := *ap
+    merged.flags = map[string]boolFlag{}
+    merged.flagsAbbr = map[string]string{}
+    merged.options = map[string]option{}
+    merged.optionsAbbr = map[string]string{}
+    merged.aliases = map[string]string{}
+    merged.deprecated = map[string]string{}
+    merged.env = map[string]string{}
+    for k, v := range ap.flags { merged.flags[k] = v }
+    for k, v := range ap.flagsAbbr { merged.flagsAbbr[k] = v }
+    for k, v := range ap.options { merged.options[k] = v }
+    for k, v := range ap.optionsAbbr { merged.optionsAbbr[k] = v }
+    for k, v := range ap.aliases { merged.aliases[k] = v }
+    for k, v := range ap.deprecated { merged.deprecated[k] = v }
+    for k, v := range ap.env { merged.env[k] = v }
+    merged.flagsOrder = append([]string{}, ap.flagsOrder...)
+    merged.optionsOrder = append([]string{}, ap.optionsOrder...)
+
+    // Arguments of the command take precedence over inherited ones
+    inherited := map[string]bool{}
+    for _, k := range view.flagsOrder {
+        _, taken := merged.flags[k]
+        if !view.flags[k].Persistent || taken || merged.isOption(k) { continue }
+        merged.flags[k] = view.flags[k]
+        merged.flagsOrder = append(merged.flagsOrder, k)
+        inherited[k] = true
+    }
+    for _, k := range view.optionsOrder {
+        _, taken := merged.options[k]
+        if !view.options[k].Persistent || taken || merged.isFlag(k) { continue }
+        merged.options[k] = view.options[k]
+        merged.optionsOrder = append(merged.optionsOrder, k)
+        inherited[k] = true
+    }
+    for a, k := range view.flagsAbbr {
+        _, taken := merged.flagsAbbr[a]
+        _, takenOp := merged.optionsAbbr[a]
+        if inherited[k] && !taken && !takenOp { merged.flagsAbbr[a] = k }
+    }
+    for a, k := range view.optionsAbbr {
+        _, taken := merged.flagsAbbr[a]
+        _, takenOp := merged.optionsAbbr[a]
+        if inherited[k] && !taken && !takenOp { merged.optionsAbbr[a] = k }
+    }
+    for a, k := range view.aliases {
+        _, taken := merged.aliases[a]
+        if inherited[k] && !taken && !merged.isFlag(a) && !merged.isOption(a) { merged.aliases[a] = k }
+    }
+    for a, succ := range view.deprecated {
+        if inherited[view.resolveAlias(a)] { merged.deprecated[a] = succ }
+    }
+    for k := range inherited {
+        variable, found := view.env[k]
+        if found { merged.env[k] = variable }
+    }
+
+    return &merged
+}
+
+// Parser describing everything the command `ap` belongs to accepts, used by
+// the man pages and the documentation: the command's own arguments and the
+// ones it inherits
+func (ap *Parser) view() *Parser {
+    if ap.parent == nil {
+        return ap
+    }
+    if ap.hasOwnArgs() {
+        return ap.inherit(ap.parent.view())
+    }
+
+    // Commands without arguments of their own accept all of their parent's
+    // arguments but not its commands
+    merged := *ap.parent.view()
+    merged.name = ap.name
+    merged.description = ap.description
+    merged.commands = map[string]command{}
+    merged.commandsOrder = []string{}
+    merged.commandAliases = map[string]string{}
+    merged.deprecatedCommands = map[string]string{}
+
+    return &merged
+}
@@ -0,0 +1,136 @@
+package args
+
+import (
+    "bytes"
+    "encoding/json"
+    "os"
+    "strings"
+    "testing"
+)
+
+func TestParsePersistent(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Width = 80
+    parser.Flag(Long("verbose"), Short('v'), Help("Verbose"), Persistent())
+    parser.Option(Long("config"), Help("Config"))
+    parser.Flag(Short('x'))
+    parser.SetPersistent("config")
+    parser.AddCommand("remote", "Manage remotes")
+    parser.AddCommand("version", "")
+    remote := parser.Command("remote")
+    remote.Flag(Long("force"), Short('f'))
+    remote.AddCommand("add", "Add a remote")
+    remote.CommandRequired = true
+    add := remote.Command("add")
+    add.Option(Long("name"), Help("Name"))
+    add.AddPositional("url", "", true, false)
+    os.Args = []string{"app.exe", "-x", "--verbose", "remote", "-f", "add", "--config", "c", "--name", "n", "URL"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Command != "remote" || strings.Join(results.CommandPath, " ") != "remote add" { t.Error(results.CommandPath) }
+    if !results.Flag["x"] || !results.Flag["verbose"] || !results.Flag["force"] { t.Error() }
+    if results.Option["config"] != "c" || results.Option["name"] != "n" { t.Error() }
+    if len(results.Positional) != 1 || results.Positional[0] != "URL" { t.Error() }
+
+    os.Args = []string{"app.exe", "remote", "-xv", "add", "u"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "invalid argument: flag x does not exist" { t.Error(err) }
+    os.Args = []string{"app.exe", "remote", "add", "-v"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "missing argument: url" { t.Error(err) }
+    if err.(*ParseError).Usage != "app remote add [-v] [--name VALUE] [--config VALUE] <url>" { t.Error(err.(*ParseError).Usage) }
+    os.Args = []string{"app.exe", "remote", "-f"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "missing argument: <command>" { t.Error(err) }
+
+    // Commands without arguments of their own accept all of their parent's
+    os.Args = []string{"app.exe", "version", "-x", "v"}
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if !results.Flag["x"] || results.Command != "version" || results.Positional[0] != "v" { t.Error() }
+    if parser.Command("missing") != nil { t.Error() }
+    if parser.SetPersistent("missing") == nil { t.Error() }
+}
+
+func TestHelpPersistent(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Width = 80
+    parser.Colors = ColorsNever
+    parser.Flag(Long("verbose"), Short('v'), Help("Verbose"), Persistent())
+    parser.Option(Long("config"), Help("Config"))
+    parser.Flag(Short('x'))
+    parser.SetPersistent("config")
+    parser.AddCommand("remote", "Manage remotes")
+    parser.AddCommand("version", "")
+    remote := parser.Command("remote")
+    remote.Flag(Long("force"), Short('f'))
+    remote.AddCommand("add", "Add a remote")
+    remote.CommandRequired = true
+    add := remote.Command("add")
+    add.Option(Long("name"), Help("Name"))
+    add.AddPositional("url", "", true, false)
+    var buf bytes.Buffer
+    parser.Command("remote").Command("add").WriteHelp(&buf)
+    expected := "app remote add - Add a remote\n\n" +
+        "USAGE\n    app remote add [--name VALUE] <url>\n\n" +
+        "OPTIONS\n    --name\n        Name\n\n" +
+        "GLOBAL OPTIONS\n    --verbose, -v\n        Verbose\n\n    --config\n        Config\n\n"
+    if buf.String() != expected { t.Error(buf.String()) }
+    buf.Reset()
+    parser.WriteHelp(&buf)
+    if strings.Contains(buf.String(), "FLAGS\n    --verbose") { t.Error(buf.String()) }
+    if !strings.Contains(buf.String(), "GLOBAL OPTIONS\n    --verbose, -v") { t.Error(buf.String()) }
+}
+
+func TestHelpRootSettings(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Width = 40
+    parser.TwoColumns = true
+    parser.Colors = ColorsAlways
+    parser.AddCommand("run", "")
+    run := parser.Command("run")
+    run.Flag(Long("fast"), Help("Go fast"))
+    var buf bytes.Buffer
+    run.WriteHelp(&buf)
+    if !strings.Contains(buf.String(), "\x1b[") { t.Error(buf.String()) }
+    if !strings.Contains(buf.String(), "Go fast") { t.Error(buf.String()) }
+    parser.Colors = ColorsNever
+    buf.Reset()
+    run.WriteHelp(&buf)
+    if !strings.Contains(buf.String(), "    --fast  Go fast\n") { t.Error(buf.String()) }
+}
+
+func TestSchemaCommands(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Width = 80
+    parser.Flag(Long("verbose"), Short('v'), Help("Verbose"), Persistent())
+    parser.Option(Long("config"), Help("Config"))
+    parser.Flag(Short('x'))
+    parser.SetPersistent("config")
+    parser.AddCommand("remote", "Manage remotes")
+    parser.AddCommand("version", "")
+    remote := parser.Command("remote")
+    remote.Flag(Long("force"), Short('f'))
+    remote.AddCommand("add", "Add a remote")
+    remote.CommandRequired = true
+    add := remote.Command("add")
+    add.Option(Long("name"), Help("Name"))
+    add.AddPositional("url", "", true, false)
+    data, err := json.Marshal(&parser)
+    if err != nil { t.Fatal(err) }
+    var restored Parser
+    err = json.Unmarshal(data, &restored)
+    if err != nil { t.Fatal(err) }
+    if !restored.isPersistent("verbose") || !restored.isPersistent("config") { t.Error() }
+    add = restored.Command("remote").Command("add")
+    if add == nil || !add.isOption("name") || add.root() != &restored { t.Fatal() }
+    if restored.Command("version").hasOwnArgs() { t.Error() }
+    os.Args = []string{"app.exe", "remote", "add", "-v", "u"}
+    results, err := restored.Parse()
+    if err != nil { t.Fatal(err) }
+    if !results.Flag["verbose"] || strings.Join(results.CommandPath, " ") != "remote add" { t.Error() }
+}
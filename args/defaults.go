@@ -0,0 +1,76 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+)
+
+/// Compute the default value of an option with a function. It's only called
+/// by `Parse` if the option isn't given on the command line or in its
+/// environment variable
+/// @param name option's name
+/// @param fn function returning the default value
+/// @return An error if the option doesn't exist
+func (ap *Parser) SetDefaultFunc(name string, fn func() (string, error)) error {
+    op, found := ap.options[name]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: option %s does not exist", name))
+    }
+    op.DefaultFunc = fn
+    ap.options[name] = op
+
+    return nil
+}
+
+/// Set the text displayed instead of an option's default value by the `Help`
+/// function and the documentation. An empty string hides the default value
+/// @param name option's name
+/// @param text displayed text
+/// @return An error if the option doesn't exist
+func (ap *Parser) SetDefaultShownAs(name string, text string) error {
+    op, found := ap.options[name]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: option %s does not exist", name))
+    }
+    op.DefaultShownAs = &text
+    ap.options[name] = op
+
+    return nil
+}
+
+// Default value of an option displayed by the `Help` function and the
+// documentation
+func (ap *Parser) shownDefault(name string) string {
+    op := ap.options[name]
+    if op.DefaultShownAs != nil {
+        return *op.DefaultShownAs
+    }
+    if op.Secret {
+        return ""
+    }
+
+    return op.DefaultsTo
+}
+
+// Computes the default values of the options that weren't given
+func (ap *Parser) applyDefaultFuncs(results *Results) error {
+    for _, k := range ap.optionsOrder {
+        op := ap.options[k]
+        if op.DefaultFunc == nil || results.IsSet(k) { continue }
+        val, err := op.DefaultFunc()
+        if err != nil {
+            return errors.New(fmt.Sprintf("missing value: %s (%s)", ap.displayName(k), err.Error()))
+        }
+        if op.Secret {
+            results.Secret[k] = Secret(val)
+        }else {
+            results.Option[k] = val
+        }
+        err = ap.setVar(k, val)
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
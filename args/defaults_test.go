@@ -0,0 +1,54 @@
+package args
+
+import (
+    "bytes"
+    "errors"
+    "os"
+    "strings"
+    "testing"
+)
+
+func TestDefaultFunc(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    calls := 0
+    parser.Option(Long("cache"), DefaultFunc(func() (string, error) {
+        calls++
+        return "/tmp/cache", nil
+    }))
+    parser.Option(Long("home"))
+    parser.SetDefaultFunc("home", func() (string, error) { return "", errors.New("no home") })
+
+    os.Args = []string{"app.exe", "--cache", "c", "--home", "h"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Option["cache"] != "c" || calls != 0 { t.Error() }
+    os.Args = []string{"app.exe", "--home", "h"}
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Option["cache"] != "/tmp/cache" || calls != 1 { t.Error() }
+    os.Args = []string{"app.exe"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "missing value: --home (no home)" { t.Error(err) }
+    if parser.SetDefaultFunc("missing", nil) == nil { t.Error() }
+}
+
+func TestHelpDefault(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Width = 80
+    parser.Colors = ColorsNever
+    parser.Option(Long("level"), Default("1"))
+    parser.Option(Long("token"), Default("s3cr3t"), DefaultShownAs(""))
+    parser.Option(Long("jobs"), Default("8"))
+    parser.SetDefaultShownAs("jobs", "number of CPUs")
+    var buf bytes.Buffer
+    parser.WriteHelp(&buf)
+    if !strings.Contains(buf.String(), "--level [default: 1]\n") { t.Error(buf.String()) }
+    if !strings.Contains(buf.String(), "--jobs [default: number of CPUs]\n") { t.Error(buf.String()) }
+    if strings.Contains(buf.String(), "s3cr3t") { t.Error(buf.String()) }
+    buf.Reset()
+    parser.Markdown(&buf)
+    if strings.Contains(buf.String(), "s3cr3t") || !strings.Contains(buf.String(), "number of CPUs") { t.Error(buf.String()) }
+    if parser.SetDefaultShownAs("missing", "") == nil { t.Error() }
+}
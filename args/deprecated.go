@@ -0,0 +1,197 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+)
+
+/// Hide flags or options from the `Help` function. They are still parsed
+/// @param names flags' or options' names
+/// @return An error if a flag or option doesn't exist
+func (ap *Parser) Hide(names ...string) error {
+    for _, name := range names {
+        fl, foundFl := ap.flags[name]
+        op, foundOp := ap.options[name]
+        if foundFl {
+            fl.Hidden = true
+            ap.flags[name] = fl
+        }else if foundOp {
+            op.Hidden = true
+            ap.options[name] = op
+        }else {
+            return errors.New(fmt.Sprintf("invalid argument: %s does not exist", name))
+        }
+    }
+
+    return nil
+}
+
+/// Hide commands from the `Help` function. They are still parsed
+/// @param names commands' names
+/// @return An error if a command doesn't exist
+func (ap *Parser) HideCommand(names ...string) error {
+    for _, name := range names {
+        cmd, found := ap.commands[name]
+        if !found {
+            return errors.New(fmt.Sprintf("invalid argument: command %s does not exist", name))
+        }
+        cmd.Hidden = true
+        ap.commands[name] = cmd
+    }
+
+    return nil
+}
+
+/// Mark a flag, option or alias as deprecated. Using it emits a warning and,
+/// if `successor` isn't empty, stores its value under `successor`. If `name`
+/// doesn't exist it's added as a deprecated alias of `successor`
+/// @param name flag's, option's or alias' name
+/// @param successor name of the flag or option replacing it. Can be empty
+/// @return An error if neither `name` nor `successor` exist or they aren't
+/// the same kind of argument
+func (ap *Parser) Deprecate(name string, successor string) error {
+    canonical := ap.resolveAlias(name)
+    _, isFl := ap.flags[canonical]
+    _, isOp := ap.options[canonical]
+    succ := ap.resolveAlias(successor)
+    _, succIsFl := ap.flags[succ]
+    _, succIsOp := ap.options[succ]
+    if successor != "" && !succIsFl && !succIsOp {
+        return errors.New(fmt.Sprintf("invalid argument: %s does not exist", successor))
+    }
+    if !isFl && !isOp {
+        if successor == "" {
+            return errors.New(fmt.Sprintf("invalid argument: %s does not exist", name))
+        }
+        ap.aliases[name] = succ
+    }else if successor != "" && (isFl != succIsFl || isOp != succIsOp) {
+        return errors.New(fmt.Sprintf(
+            "invalid argument: %s and %s are different kinds of arguments", name, successor,
+        ))
+    }
+    ap.deprecated[name] = succ
+
+    return nil
+}
+
+/// Mark a command or command alias as deprecated. Using it emits a warning
+/// and, if `successor` isn't empty, selects `successor` instead. If `name`
+/// doesn't exist it's added as a deprecated alias of `successor`
+/// @param name command's or alias' name
+/// @param successor name of the command replacing it. Can be empty
+/// @return An error if neither `name` nor `successor` exist
+func (ap *Parser) DeprecateCommand(name string, successor string) error {
+    canonical := ap.resolveCommandAlias(name)
+    _, found := ap.commands[canonical]
+    succ := ap.resolveCommandAlias(successor)
+    _, succFound := ap.commands[succ]
+    if successor != "" && !succFound {
+        return errors.New(fmt.Sprintf("invalid argument: command %s does not exist", successor))
+    }
+    if !found {
+        if successor == "" {
+            return errors.New(fmt.Sprintf("invalid argument: command %s does not exist", name))
+        }
+        ap.commandAliases[name] = succ
+    }
+    ap.deprecatedCommands[name] = succ
+
+    return nil
+}
+
+func (ap *Parser) isHidden(name string) bool {
+    group, grouped := ap.argGroup[name]
+    if grouped && ap.groups[group].Hidden {
+        return true
+    }
+
+    return ap.flags[name].Hidden || ap.options[name].Hidden
+}
+
+// Aliases displayed by the `Help` function. Deprecated aliases are left out
+func (ap *Parser) visibleAliases() map[string][]string {
+    aliases := map[string]string{}
+    for k, v := range ap.aliases {
+        _, dep := ap.deprecated[k]
+        if !dep { aliases[k] = v }
+    }
+
+    return invertAliases(aliases)
+}
+
+// Returns the name of a flag or option as typed on the command line
+func (ap *Parser) displayName(name string) string {
+    fl, foundFl := ap.flags[name]
+    op, foundOp := ap.options[name]
+    if (foundFl && fl.ShortOnly) || (foundOp && op.ShortOnly) {
+        return ap.syntax().shortPrefix() + name
+    }
+
+    return ap.syntax().longPrefix() + name
+}
+
+func (ap *Parser) deprecationHint(name string) string {
+    succ, dep := ap.deprecated[name]
+    if !dep {
+        return ""
+    }
+    if succ == "" || succ == name {
+        return " (deprecated)"
+    }
+
+    return fmt.Sprintf(" (deprecated, use %s)", ap.displayName(succ))
+}
+
+func (ap *Parser) warn(warning string) {
+    // Commands use the warning settings of the top level parser
+    root := ap.root()
+    if root.OnWarning != nil {
+        root.OnWarning(warning)
+    }else if root.Warnings != nil {
+        root.colored(root.Warnings, root.theme().Warning, "warning:")
+        fmt.Fprintf(root.Warnings, " %s\n", warning)
+    }
+}
+
+// Warns if the argument is deprecated and returns the name its value is
+// stored under
+// @param typed argument as it appears on the command line
+// @param long long name or alias used. Empty if a short name was used
+// @param name flag's or option's name
+func (ap *Parser) checkDeprecated(typed string, long string, name string) string {
+    succ, dep := "", false
+    if long != "" {
+        succ, dep = ap.deprecated[long]
+    }
+    if !dep {
+        succ, dep = ap.deprecated[name]
+    }
+    if !dep {
+        return name
+    }
+    if succ == "" {
+        ap.warn(fmt.Sprintf("%s is deprecated", typed))
+        return name
+    }
+    ap.warn(fmt.Sprintf("%s is deprecated, use %s instead", typed, ap.displayName(succ)))
+
+    return succ
+}
+
+// Warns if the command is deprecated and returns the command to select
+func (ap *Parser) checkDeprecatedCommand(typed string, name string) string {
+    succ, dep := ap.deprecatedCommands[typed]
+    if !dep {
+        succ, dep = ap.deprecatedCommands[name]
+    }
+    if !dep {
+        return name
+    }
+    if succ == "" {
+        ap.warn(fmt.Sprintf("%s is deprecated", typed))
+        return name
+    }
+    ap.warn(fmt.Sprintf("%s is deprecated, use %s instead", typed, succ))
+
+    return succ
+}
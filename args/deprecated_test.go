@@ -0,0 +1,50 @@
+package args
+
+import (
+    "bytes"
+    "os"
+    "testing"
+)
+
+func TestDeprecated(t *testing.T) {
+    os.Args = []string{"app.exe", "old-run", "--colour", "--old-level", "2", "-x"}
+    var parser Parser
+    parser.Init("Test", "")
+    warnings := []string{}
+    parser.OnWarning = func(warning string) { warnings = append(warnings, warning) }
+    parser.AddCommand("run", "")
+    parser.AddFlag("color", "", '\000')
+    parser.AddFlag("secret", "", 'x')
+    parser.AddOption("level", "", '\000', "1", []string{})
+    parser.Option(Long("old-level"), Deprecated("level"))
+    if parser.Deprecate("colour", "color") != nil { t.Error() }
+    if parser.Deprecate("missing", "") == nil { t.Error() }
+    if parser.Deprecate("secret", "level") == nil { t.Error() }
+    if parser.DeprecateCommand("old-run", "run") != nil { t.Error() }
+    if parser.Hide("secret") != nil { t.Error() }
+    if parser.HideCommand("missing") == nil { t.Error() }
+    results, err := parser.Parse()
+    if err != nil { t.Error(err) }
+    if results.Command != "run" { t.Error() }
+    if !results.Flag["color"] { t.Error() }
+    if !results.Flag["secret"] { t.Error() }
+    if results.Option["level"] != "2" { t.Error() }
+    if results.Option["old-level"] != "" { t.Error() }
+    if len(warnings) != 3 { t.Fatal(warnings) }
+    if warnings[0] != "old-run is deprecated, use run instead" { t.Error(warnings[0]) }
+    if warnings[1] != "--colour is deprecated, use --color instead" { t.Error(warnings[1]) }
+    if warnings[2] != "--old-level is deprecated, use --level instead" { t.Error(warnings[2]) }
+}
+
+func TestDeprecatedWriter(t *testing.T) {
+    os.Args = []string{"app.exe", "-v"}
+    var parser Parser
+    parser.Init("Test", "")
+    parser.Colors = ColorsNever
+    var buf bytes.Buffer
+    parser.Warnings = &buf
+    parser.Flag(Short('v'), Deprecated(""))
+    _, err := parser.Parse()
+    if err != nil { t.Error(err) }
+    if buf.String() != "warning: -v is deprecated\n" { t.Error(buf.String()) }
+}
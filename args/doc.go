@@ -0,0 +1,398 @@
+package args
+
+import (
+    "fmt"
+    "html"
+    "io"
+    "strings"
+)
+
+// Returns the anchor of an argument in the Markdown and HTML documentation.
+// `kind` is one of "command", "flag", "option" or "env"
+func docAnchor(kind string, name string) string {
+    var b strings.Builder
+    b.WriteString(kind + "-")
+    for _, r := range name {
+        if (r >= 'a' && r <= 'z') || (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') || r == '_' || r == '-' {
+            b.WriteRune(r)
+        }else {
+            b.WriteRune('-')
+        }
+    }
+
+    return b.String()
+}
+
+// Label and value pairs describing an option's default value, accepted
+// values, validators and environment variable
+func (ap *Parser) docOptionDetails(name string) [][2]string {
+    op := ap.options[name]
+    details := [][2]string{}
+    if ap.shownDefault(name) != "" {
+        details = append(details, [2]string{"Default", ap.shownDefault(name)})
+    }
+    allowed := append([]string{}, op.Allowed...)
+    for _, c := range op.Choices {
+        allowed = append(allowed, c.Value)
+    }
+    if len(allowed) != 0 {
+        details = append(details, [2]string{"Allowed", strings.Join(allowed, "|")})
+    }
+    for _, v := range op.Validators {
+        if v.Description != "" {
+            details = append(details, [2]string{"Format", v.Description})
+        }
+    }
+    variable, found := ap.env[name]
+    if found {
+        details = append(details, [2]string{"Environment", variable})
+    }
+
+    return details
+}
+
+type docSection struct {
+    title string
+    description string
+    names []string
+}
+
+// Sections of flags and options: the ungrouped flags, the ungrouped options
+// and the visible groups
+func (ap *Parser) docSections() []docSection {
+    sections := []docSection{
+        {"Flags", "", ap.ungrouped(ap.flagsOrder)},
+        {"Options", "", ap.ungrouped(ap.optionsOrder)},
+    }
+    for _, name := range ap.visibleGroups() {
+        g := ap.groups[name]
+        sections = append(sections, docSection{name, g.Description, g.args})
+    }
+
+    return sections
+}
+
+// Visible commands with arguments of their own documented after the parser,
+// followed by their own commands
+func (ap *Parser) docCommands() []*Parser {
+    cmds := []*Parser{}
+    for _, k := range ap.commandsOrder {
+        cmd := ap.commands[k]
+        if cmd.Hidden || !cmd.parser.hasOwnArgs() { continue }
+        cmds = append(cmds, cmd.parser)
+        cmds = append(cmds, cmd.parser.docCommands()...)
+    }
+
+    return cmds
+}
+
+// Prefix of the anchors of a command's documentation, such as `command-run-`.
+// Empty for the top level parser
+func (ap *Parser) docPrefix() string {
+    if ap.parent == nil {
+        return ""
+    }
+    path := strings.TrimSpace(strings.TrimPrefix(ap.name, ap.root().name))
+
+    return docAnchor("command", path) + "-"
+}
+
+// Kind of an argument's anchor, "flag" or "option"
+func (ap *Parser) docKind(name string) string {
+    if ap.isFlag(name) {
+        return "flag"
+    }
+
+    return "option"
+}
+
+func markdownCode(text string) string {
+    ticks := "`"
+    for strings.Contains(text, ticks) {
+        ticks += "`"
+    }
+    if strings.HasPrefix(text, "`") || strings.HasSuffix(text, "`") {
+        return ticks + " " + text + " " + ticks
+    }
+
+    return ticks + text + ticks
+}
+
+func markdownNames(names []string) string {
+    for i, n := range names {
+        names[i] = markdownCode(n)
+    }
+
+    return strings.Join(names, ", ")
+}
+
+// Writes the Markdown documentation of a parser returned by `view`. `level`
+// is the level of the title's heading and `prefix` is prepended to anchors
+func (ap *Parser) writeMarkdown(b *strings.Builder, level int, prefix string) {
+    heading := strings.Repeat("#", level)
+    if ap.name != "" {
+        fmt.Fprintf(b, "%s %s\n\n", heading, ap.name)
+    }
+    if ap.description != "" {
+        fmt.Fprintf(b, "%s\n\n", strings.TrimRight(ap.description, "\n"))
+    }
+    fmt.Fprintf(b, "```\n%s\n```\n\n", ap.usage())
+
+    if len(ap.commands) != 0 {
+        b.WriteString(heading + "# Commands\n\n")
+        aliases := invertAliases(ap.commandAliases)
+        for _, k := range ap.commandsOrder {
+            cmd := ap.commands[k]
+            if cmd.Hidden { continue }
+            fmt.Fprintf(b, "<a id=\"%s\"></a>\n", prefix + docAnchor("command", k))
+            fmt.Fprintf(b, "%s## %s\n\n", heading, markdownCode(k))
+            visible := []string{}
+            for _, a := range aliases[k] {
+                _, dep := ap.deprecatedCommands[a]
+                if !dep { visible = append(visible, a) }
+            }
+            if len(visible) != 0 {
+                fmt.Fprintf(b, "Aliases: %s\n\n", markdownNames(visible))
+            }
+            succ, dep := ap.deprecatedCommands[k]
+            if dep && succ != "" {
+                fmt.Fprintf(b, "**Deprecated**, use [%s](#%s)\n\n", markdownCode(succ), prefix + docAnchor("command", succ))
+            }else if dep {
+                b.WriteString("**Deprecated**\n\n")
+            }
+            if cmd.Help != "" {
+                fmt.Fprintf(b, "%s\n\n", strings.TrimRight(cmd.Help, "\n"))
+            }
+        }
+    }
+
+    sections := ap.docSections()
+    for _, sec := range sections {
+        visible := []string{}
+        for _, k := range sec.names {
+            if !ap.isHidden(k) { visible = append(visible, k) }
+        }
+        if len(visible) == 0 { continue }
+        fmt.Fprintf(b, "%s# %s\n\n", heading, sec.title)
+        if sec.description != "" {
+            fmt.Fprintf(b, "%s\n\n", strings.TrimRight(sec.description, "\n"))
+        }
+        for _, k := range visible {
+            kind := ap.docKind(k)
+            fmt.Fprintf(b, "<a id=\"%s\"></a>\n", prefix + docAnchor(kind, k))
+            fmt.Fprintf(b, "%s## %s\n\n", heading, markdownNames(ap.argNames(k)))
+            succ, dep := ap.deprecated[k]
+            if dep && succ != "" && succ != k {
+                fmt.Fprintf(
+                    b, "**Deprecated**, use [%s](#%s)\n\n",
+                    markdownCode(ap.displayName(succ)), prefix + docAnchor(kind, succ),
+                )
+            }else if dep {
+                b.WriteString("**Deprecated**\n\n")
+            }
+            help := ap.flags[k].Help
+            if kind == "option" {
+                help = ap.options[k].Help
+            }
+            if help != "" {
+                fmt.Fprintf(b, "%s\n\n", strings.TrimRight(help, "\n"))
+            }
+            if kind == "option" {
+                details := ap.docOptionDetails(k)
+                for _, d := range details {
+                    fmt.Fprintf(b, "- %s: %s\n", d[0], markdownCode(d[1]))
+                }
+                for _, c := range ap.options[k].Choices {
+                    if c.Help != "" {
+                        fmt.Fprintf(b, "- %s: %s\n", markdownCode(c.Value), c.Help)
+                    }
+                }
+                if len(details) != 0 { b.WriteString("\n") }
+            }else if variable, found := ap.env[k]; found {
+                fmt.Fprintf(b, "- Environment: %s\n\n", markdownCode(variable))
+            }
+        }
+    }
+
+    bindings := ap.envBindings()
+    if len(bindings) != 0 {
+        b.WriteString(heading + "# Environment\n\n")
+        b.WriteString("| Variable | Argument |\n")
+        b.WriteString("| --- | --- |\n")
+        for _, bind := range bindings {
+            kind := "option"
+            if _, isFl := ap.flags[bind[0]]; isFl { kind = "flag" }
+            fmt.Fprintf(
+                b, "| <a id=\"%s\"></a>%s | [%s](#%s) |\n",
+                prefix + docAnchor("env", bind[1]), markdownCode(bind[1]),
+                markdownCode(ap.displayName(bind[0])), prefix + docAnchor(kind, bind[0]),
+            )
+        }
+        b.WriteString("\n")
+    }
+}
+
+/// Write the reference documentation in Markdown. Each command, flag, option
+/// and environment variable gets an anchor such as `option-name`. Commands
+/// with arguments of their own are documented after the parser, with anchors
+/// such as `command-run-option-name`
+/// @param w where the documentation is written
+/// @return An error if writing fails
+func (ap *Parser) Markdown(w io.Writer) error {
+    var b strings.Builder
+    ap.view().writeMarkdown(&b, 1, ap.docPrefix())
+    for _, cmd := range ap.docCommands() {
+        cmd.view().writeMarkdown(&b, 2, cmd.docPrefix())
+    }
+
+    _, err := io.WriteString(w, strings.TrimRight(b.String(), "\n") + "\n")
+    return err
+}
+
+func htmlParagraphs(b *strings.Builder, text string) {
+    for _, p := range strings.Split(strings.TrimRight(text, "\n"), "\n\n") {
+        p = strings.ReplaceAll(html.EscapeString(p), "\n", "<br>\n")
+        fmt.Fprintf(b, "<p>%s</p>\n", p)
+    }
+}
+
+func htmlNames(names []string) string {
+    for i, n := range names {
+        names[i] = "<code>" + html.EscapeString(n) + "</code>"
+    }
+
+    return strings.Join(names, ", ")
+}
+
+// Writes the HTML documentation of a parser returned by `view`. `level` is
+// the level of the title's heading and `prefix` is prepended to anchors
+func (ap *Parser) writeHTML(b *strings.Builder, level int, prefix string) {
+    if ap.name != "" {
+        fmt.Fprintf(b, "<h%d>%s</h%d>\n", level, html.EscapeString(ap.name), level)
+    }
+    if ap.description != "" {
+        htmlParagraphs(b, ap.description)
+    }
+    fmt.Fprintf(b, "<pre><code>%s</code></pre>\n", html.EscapeString(ap.usage()))
+
+    if len(ap.commands) != 0 {
+        fmt.Fprintf(b, "<h%d>Commands</h%d>\n<dl>\n", level + 1, level + 1)
+        aliases := invertAliases(ap.commandAliases)
+        for _, k := range ap.commandsOrder {
+            cmd := ap.commands[k]
+            if cmd.Hidden { continue }
+            names := []string{k}
+            for _, a := range aliases[k] {
+                _, dep := ap.deprecatedCommands[a]
+                if !dep { names = append(names, a) }
+            }
+            fmt.Fprintf(b, "<dt id=\"%s\">%s</dt>\n<dd>\n", prefix + docAnchor("command", k), htmlNames(names))
+            succ, dep := ap.deprecatedCommands[k]
+            if dep && succ != "" {
+                fmt.Fprintf(
+                    b, "<p><strong>Deprecated</strong>, use <a href=\"#%s\"><code>%s</code></a></p>\n",
+                    prefix + docAnchor("command", succ), html.EscapeString(succ),
+                )
+            }else if dep {
+                b.WriteString("<p><strong>Deprecated</strong></p>\n")
+            }
+            if cmd.Help != "" {
+                htmlParagraphs(b, cmd.Help)
+            }
+            b.WriteString("</dd>\n")
+        }
+        b.WriteString("</dl>\n")
+    }
+
+    sections := ap.docSections()
+    for _, sec := range sections {
+        visible := []string{}
+        for _, k := range sec.names {
+            if !ap.isHidden(k) { visible = append(visible, k) }
+        }
+        if len(visible) == 0 { continue }
+        fmt.Fprintf(b, "<h%d>%s</h%d>\n", level + 1, html.EscapeString(sec.title), level + 1)
+        if sec.description != "" {
+            htmlParagraphs(b, sec.description)
+        }
+        b.WriteString("<dl>\n")
+        for _, k := range visible {
+            kind := ap.docKind(k)
+            fmt.Fprintf(b, "<dt id=\"%s\">%s</dt>\n<dd>\n", prefix + docAnchor(kind, k), htmlNames(ap.argNames(k)))
+            succ, dep := ap.deprecated[k]
+            if dep && succ != "" && succ != k {
+                fmt.Fprintf(
+                    b, "<p><strong>Deprecated</strong>, use <a href=\"#%s\"><code>%s</code></a></p>\n",
+                    prefix + docAnchor(kind, succ), html.EscapeString(ap.displayName(succ)),
+                )
+            }else if dep {
+                b.WriteString("<p><strong>Deprecated</strong></p>\n")
+            }
+            help := ap.flags[k].Help
+            if kind == "option" {
+                help = ap.options[k].Help
+            }
+            if help != "" {
+                htmlParagraphs(b, help)
+            }
+            details := [][2]string{}
+            if kind == "option" {
+                details = ap.docOptionDetails(k)
+                for _, c := range ap.options[k].Choices {
+                    if c.Help != "" { details = append(details, [2]string{c.Value, c.Help}) }
+                }
+            }else if variable, found := ap.env[k]; found {
+                details = append(details, [2]string{"Environment", variable})
+            }
+            if len(details) != 0 {
+                b.WriteString("<ul>\n")
+                for _, d := range details {
+                    fmt.Fprintf(
+                        b, "<li>%s: <code>%s</code></li>\n",
+                        html.EscapeString(d[0]), html.EscapeString(d[1]),
+                    )
+                }
+                b.WriteString("</ul>\n")
+            }
+            b.WriteString("</dd>\n")
+        }
+        b.WriteString("</dl>\n")
+    }
+
+    bindings := ap.envBindings()
+    if len(bindings) != 0 {
+        fmt.Fprintf(b, "<h%d>Environment</h%d>\n<table>\n", level + 1, level + 1)
+        b.WriteString("<tr><th>Variable</th><th>Argument</th></tr>\n")
+        for _, bind := range bindings {
+            kind := "option"
+            if _, isFl := ap.flags[bind[0]]; isFl { kind = "flag" }
+            fmt.Fprintf(
+                b, "<tr id=\"%s\"><td><code>%s</code></td><td><a href=\"#%s\"><code>%s</code></a></td></tr>\n",
+                prefix + docAnchor("env", bind[1]), html.EscapeString(bind[1]),
+                prefix + docAnchor(kind, bind[0]), html.EscapeString(ap.displayName(bind[0])),
+            )
+        }
+        b.WriteString("</table>\n")
+    }
+}
+
+/// Write the reference documentation as a standalone HTML page. Each command,
+/// flag, option and environment variable gets an anchor such as
+/// `option-name`. Commands with arguments of their own are documented after
+/// the parser, with anchors such as `command-run-option-name`
+/// @param w where the documentation is written
+/// @return An error if writing fails
+func (ap *Parser) HTML(w io.Writer) error {
+    var b strings.Builder
+    b.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+    fmt.Fprintf(&b, "<title>%s</title>\n", html.EscapeString(ap.name))
+    b.WriteString("</head>\n<body>\n")
+    ap.view().writeHTML(&b, 1, ap.docPrefix())
+    for _, cmd := range ap.docCommands() {
+        cmd.view().writeHTML(&b, 2, cmd.docPrefix())
+    }
+
+    b.WriteString("</body>\n</html>\n")
+    _, err := io.WriteString(w, b.String())
+    return err
+}
@@ -0,0 +1,100 @@
+package args
+
+import (
+    "bytes"
+    "strings"
+    "testing"
+)
+
+func TestMarkdown(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "This is a test program\n\nIt does things")
+    parser.AddCommand("run", "Run something")
+    parser.AddCommand("debug", "")
+    parser.HideCommand("debug")
+    parser.AddFlag("verbose", "Print more", 'v')
+    parser.AddFlag("secret", "", '\000')
+    parser.Hide("secret")
+    parser.AddOption("mode", "Mode to use", 'm', "fast", []string{"fast", "slow"})
+    parser.Option(Long("token"), Env("APP_TOKEN"))
+    parser.AddCommandAlias("run", "r")
+    var buf bytes.Buffer
+    if parser.Markdown(&buf) != nil { t.Error() }
+    doc := buf.String()
+    expected := []string{
+        "# app\n\nThis is a test program\n\nIt does things\n\n",
+        "## Commands\n\n<a id=\"command-run\"></a>\n### `run`\n\nAliases: `r`\n\nRun something\n\n",
+        "## Flags\n\n<a id=\"flag-verbose\"></a>\n### `--verbose`, `-v`\n\nPrint more\n\n",
+        "<a id=\"option-mode\"></a>\n### `--mode`, `-m`\n\nMode to use\n\n- Default: `fast`\n- Allowed: `fast|slow`\n",
+        "| <a id=\"env-APP_TOKEN\"></a>`APP_TOKEN` | [`--token`](#option-token) |\n",
+    }
+    for _, e := range expected {
+        if !strings.Contains(doc, e) { t.Error(e) }
+    }
+    if strings.Contains(doc, "secret") || strings.Contains(doc, "debug") { t.Error() }
+}
+
+func TestHTML(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.AddCommand("run", "Run something")
+    parser.AddOption("mode", "Mode to use", 'm', "fast", []string{"fast", "slow"})
+    parser.Option(Long("token"), Env("APP_TOKEN"))
+    parser.AddOption("expr", "a < b", '\000', "", []string{})
+    var buf bytes.Buffer
+    if parser.HTML(&buf) != nil { t.Error() }
+    doc := buf.String()
+    expected := []string{
+        "<!DOCTYPE html>\n",
+        "<title>app</title>\n",
+        "<dt id=\"command-run\"><code>run</code></dt>\n<dd>\n<p>Run something</p>\n</dd>\n",
+        "<dt id=\"option-mode\"><code>--mode</code>, <code>-m</code></dt>\n",
+        "<li>Default: <code>fast</code></li>\n",
+        "<p>a &lt; b</p>\n",
+        "<tr id=\"env-APP_TOKEN\">",
+        "</body>\n</html>\n",
+    }
+    for _, e := range expected {
+        if !strings.Contains(doc, e) { t.Error(e) }
+    }
+}
+
+func TestCommandDocs(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.AddFlag("verbose", "Print more", 'v')
+    parser.Flag(Long("quiet"), Short('q'), Persistent())
+    parser.AddCommand("run", "Run something")
+    parser.AddCommand("version", "")
+    run := parser.Command("run")
+    run.AddFlag("fast", "Go fast", 'f')
+    run.AddCommand("job", "Run a job")
+    run.Command("job").AddOption("id", "Job id", '\000', "", []string{})
+    var buf bytes.Buffer
+    if parser.Markdown(&buf) != nil { t.Error() }
+    doc := buf.String()
+    expected := []string{
+        "## app run\n\nRun something\n\n```\napp run [-f] [-q] [<command>] [ARGS...]\n```\n\n",
+        "### Commands\n\n<a id=\"command-run-command-job\"></a>\n#### `job`\n\n",
+        "### Flags\n\n<a id=\"command-run-flag-fast\"></a>\n#### `--fast`, `-f`\n\nGo fast\n\n",
+        "<a id=\"command-run-flag-quiet\"></a>\n#### `--quiet`, `-q`\n\n",
+        "## app run job\n\nRun a job\n\n",
+        "### Options\n\n<a id=\"command-run-job-option-id\"></a>\n#### `--id`\n\nJob id\n",
+    }
+    for _, e := range expected {
+        if !strings.Contains(doc, e) { t.Error(e) }
+    }
+    if strings.Contains(doc, "## app version") { t.Error(doc) }
+
+    buf.Reset()
+    if parser.HTML(&buf) != nil { t.Error() }
+    doc = buf.String()
+    expected = []string{
+        "<h2>app run</h2>\n<p>Run something</p>\n",
+        "<h3>Flags</h3>\n<dl>\n<dt id=\"command-run-flag-fast\"><code>--fast</code>, <code>-f</code></dt>\n",
+        "<dt id=\"command-run-job-option-id\"><code>--id</code></dt>\n",
+    }
+    for _, e := range expected {
+        if !strings.Contains(doc, e) { t.Error(e) }
+    }
+}
@@ -0,0 +1,68 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "strconv"
+)
+
+/// Read a flag's or option's value from an environment variable when it isn't
+/// given on the command line. Flags accept the values accepted by
+/// `strconv.ParseBool`
+/// @param name flag's or option's name
+/// @param variable environment variable's name
+/// @return An error if the flag or option doesn't exist
+func (ap *Parser) BindEnv(name string, variable string) error {
+    _, foundFl := ap.flags[name]
+    _, foundOp := ap.options[name]
+    if !foundFl && !foundOp {
+        return errors.New(fmt.Sprintf("invalid argument: %s does not exist", name))
+    }
+    ap.env[name] = variable
+
+    return nil
+}
+
+// Environment variables bound to the flags and options, in the order they
+// were added
+func (ap *Parser) envBindings() [][2]string {
+    bindings := [][2]string{}
+    for _, k := range ap.flagsOrder {
+        variable, found := ap.env[k]
+        if found { bindings = append(bindings, [2]string{k, variable}) }
+    }
+    for _, k := range ap.optionsOrder {
+        variable, found := ap.env[k]
+        if found { bindings = append(bindings, [2]string{k, variable}) }
+    }
+
+    return bindings
+}
+
+// Overrides the default values with the values of the bound environment
+// variables, in the order the flags and options were added
+func (ap *Parser) applyEnv(results *Results) error {
+    for _, bind := range ap.envBindings() {
+        name, variable := bind[0], bind[1]
+        val, found := os.LookupEnv(variable)
+        if !found { continue }
+        _, isFl := ap.flags[name]
+        if isFl {
+            b, err := strconv.ParseBool(val)
+            if err != nil {
+                return errors.New(fmt.Sprintf("invalid value: %s -> %s", variable, val))
+            }
+            results.Flag[name] = b
+            if ap.flags[name].Var != nil { *ap.flags[name].Var = b }
+            results.setOrigin(name, ValueOrigin{Source: SourceEnv, Index: -1, Token: variable})
+            continue
+        }
+        err := ap.setOption(results, name, val, ValueOrigin{Source: SourceEnv, Index: -1, Token: variable})
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
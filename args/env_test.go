@@ -0,0 +1,50 @@
+package args
+
+import (
+    "os"
+    "strings"
+    "testing"
+)
+
+func TestParseEnv(t *testing.T) {
+    os.Args = []string{"app.exe", "--op02", "arg"}
+    os.Setenv("ARGS_TEST_FLAG", "true")
+    os.Setenv("ARGS_TEST_OP01", "env")
+    os.Setenv("ARGS_TEST_OP02", "env")
+    defer os.Unsetenv("ARGS_TEST_FLAG")
+    defer os.Unsetenv("ARGS_TEST_OP01")
+    defer os.Unsetenv("ARGS_TEST_OP02")
+    var parser Parser
+    parser.Init("Test", "")
+    parser.AddFlag("flag", "", '\000')
+    parser.Option(Long("op01"), Default("default"), Env("ARGS_TEST_OP01"))
+    parser.AddOption("op02", "", '\000', "default", []string{})
+    parser.AddOption("op03", "", '\000', "default", []string{})
+    if parser.BindEnv("flag", "ARGS_TEST_FLAG") != nil { t.Error() }
+    if parser.BindEnv("op02", "ARGS_TEST_OP02") != nil { t.Error() }
+    if parser.BindEnv("op03", "ARGS_TEST_OP03") != nil { t.Error() }
+    if parser.BindEnv("missing", "ARGS_TEST_MISSING") == nil { t.Error() }
+    results, err := parser.Parse()
+    if err != nil { t.Error(err) }
+    if !results.Flag["flag"] { t.Error() }
+    if results.Option["op01"] != "env" { t.Error() }
+    if results.Option["op02"] != "arg" { t.Error() }
+    if results.Option["op03"] != "default" { t.Error() }
+
+    os.Setenv("ARGS_TEST_FLAG", "maybe")
+    _, err = parser.Parse()
+    if err == nil { t.Error() }
+
+    // Bindings are applied in the order the options were added
+    os.Setenv("ARGS_TEST_OP02", "x")
+    os.Setenv("ARGS_TEST_OP03", "x")
+    parser.SetChoices("op02", Choice{Value: "a"})
+    parser.SetChoices("op03", Choice{Value: "a"})
+    os.Setenv("ARGS_TEST_FLAG", "true")
+    defer os.Unsetenv("ARGS_TEST_OP03")
+    os.Args = []string{"app.exe"}
+    for i := 0; i < 10; i++ {
+        _, err = parser.Parse()
+        if err == nil || !strings.Contains(err.Error(), "op02") { t.Fatal(err) }
+    }
+}
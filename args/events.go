@@ -0,0 +1,48 @@
+package args
+
+type EventKind int
+
+const (
+    /// A flag was set. `Event.Name` is the flag's name
+    EventFlag EventKind = iota
+    /// An option was given. `Event.Name` is the option's name and
+    /// `Event.Value` its value
+    EventOption
+    /// A positional argument was given. `Event.Value` is the argument
+    EventPositional
+    /// A command was given. `Event.Name` is the command's name
+    EventCommand
+    /// `--` was given. The following arguments are positional
+    EventTerminator
+)
+
+func (k EventKind) String() string {
+    switch k {
+        case EventFlag:
+            return "flag"
+        case EventOption:
+            return "option"
+        case EventPositional:
+            return "positional"
+        case EventCommand:
+            return "command"
+    }
+
+    return "terminator"
+}
+
+type Event struct {
+    Kind EventKind
+    /// Name of the flag, option or command
+    Name string
+    /// Value of the option or positional argument
+    Value string
+    /// Index of the argument, without the program's name
+    Index int
+    /// Argument the event comes from
+    Token string
+}
+
+func (r *Results) addEvent(kind EventKind, name string, value string, args []string, i int) {
+    r.Events = append(r.Events, Event{Kind: kind, Name: name, Value: value, Index: i, Token: args[i]})
+}
@@ -0,0 +1,32 @@
+package args
+
+import (
+    "os"
+    "testing"
+)
+
+func TestEvents(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.AddCommand("convert", "")
+    parser.Flag(Long("verbose"), Short('v'))
+    parser.Option(Long("input"), Short('i'))
+    os.Args = []string{"app.exe", "-v", "convert", "-i", "a", "x", "--input=b", "y", "--", "-z"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    expected := []Event{
+        {Kind: EventFlag, Name: "verbose", Index: 0, Token: "-v"},
+        {Kind: EventCommand, Name: "convert", Index: 1, Token: "convert"},
+        {Kind: EventOption, Name: "input", Value: "a", Index: 2, Token: "-i"},
+        {Kind: EventPositional, Value: "x", Index: 4, Token: "x"},
+        {Kind: EventOption, Name: "input", Value: "b", Index: 5, Token: "--input=b"},
+        {Kind: EventPositional, Value: "y", Index: 6, Token: "y"},
+        {Kind: EventTerminator, Index: 7, Token: "--"},
+        {Kind: EventPositional, Value: "-z", Index: 8, Token: "-z"},
+    }
+    if len(results.Events) != len(expected) { t.Fatal(results.Events) }
+    for ii, e := range expected {
+        if results.Events[ii] != e { t.Error(ii, results.Events[ii]) }
+    }
+    if EventOption.String() != "option" || EventTerminator.String() != "terminator" { t.Error() }
+}
@@ -0,0 +1,147 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+)
+
+type FileAccess int
+
+const (
+    /// The file must exist and be readable. `-` is stdin
+    FileRead FileAccess = iota
+    /// The file is created or truncated. `-` is stdout
+    FileWrite
+    /// The file is created or appended to. `-` is stdout
+    FileAppend
+)
+
+/// Make an option a file option. The file is checked by `Parse` and opened by
+/// `Results.File`
+/// @param name option's name
+/// @param access how the file is used
+/// @return An error if the option doesn't exist
+func (ap *Parser) SetFile(name string, access FileAccess) error {
+    op, found := ap.options[name]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: option %s does not exist", name))
+    }
+    op.File = true
+    op.Access = access
+    if op.Metavar == "" {
+        op.Metavar = "FILE"
+    }
+    ap.options[name] = op
+
+    return nil
+}
+
+// Describes why a file can't be used, without its path
+func fileError(err error) error {
+    switch {
+        case os.IsNotExist(err):
+            return errors.New("file does not exist")
+        case os.IsPermission(err):
+            return errors.New("permission denied")
+    }
+    var pathErr *os.PathError
+    if errors.As(err, &pathErr) {
+        return pathErr.Err
+    }
+
+    return err
+}
+
+// Checks that the file given to a file option can be opened without opening
+// it for longer than needed
+func checkFile(path string, access FileAccess) error {
+    if path == "-" {
+        return nil
+    }
+    info, err := os.Stat(path)
+    if err != nil {
+        if access == FileRead || !os.IsNotExist(err) {
+            return fileError(err)
+        }
+        dir, err := os.Stat(filepath.Dir(path))
+        if (err == nil && !dir.IsDir()) || os.IsNotExist(err) {
+            return errors.New("directory does not exist")
+        }
+        if err != nil {
+            return fileError(err)
+        }
+
+        return nil
+    }
+    if info.IsDir() {
+        return errors.New("is a directory")
+    }
+    flag := os.O_RDONLY
+    if access != FileRead {
+        flag = os.O_WRONLY
+    }
+    file, err := os.OpenFile(path, flag, 0)
+    if err != nil {
+        return fileError(err)
+    }
+
+    return file.Close()
+}
+
+/// Open the file given to a file option. The file is opened the first time
+/// and the same file is returned afterwards. `-` returns stdin or stdout
+/// @param name option's name
+/// @return The file, nil if the option has no value, or an error if the
+/// option isn't a file option or the file can't be opened
+func (r *Results) File(name string) (*os.File, error) {
+    access, found := r.fileAccess[name]
+    if !found {
+        return nil, errors.New(fmt.Sprintf("invalid argument: %s is not a file option", name))
+    }
+    file, opened := r.files[name]
+    if opened {
+        return file, nil
+    }
+    path := r.Option[name]
+    var err error
+    switch {
+        case path == "":
+            return nil, nil
+        case path == "-" && access == FileRead:
+            file = os.Stdin
+        case path == "-":
+            file = os.Stdout
+        case access == FileRead:
+            file, err = os.Open(path)
+        case access == FileWrite:
+            file, err = os.Create(path)
+        default:
+            file, err = os.OpenFile(path, os.O_WRONLY | os.O_CREATE | os.O_APPEND, 0666)
+    }
+    if err != nil {
+        return nil, err
+    }
+    if r.files == nil {
+        r.files = map[string]*os.File{}
+    }
+    r.files[name] = file
+
+    return file, nil
+}
+
+/// Close the files opened by `File`. Stdin and stdout are left open
+/// @return The first error returned while closing the files
+func (r *Results) Close() error {
+    var first error
+    for name, file := range r.files {
+        if file != os.Stdin && file != os.Stdout {
+            err := file.Close()
+            if err != nil && first == nil { first = err }
+        }
+        delete(r.files, name)
+    }
+
+    return first
+}
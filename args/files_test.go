@@ -0,0 +1,89 @@
+package args
+
+import (
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestFileOptions(t *testing.T) {
+    dir := t.TempDir()
+    input := filepath.Join(dir, "in.txt")
+    output := filepath.Join(dir, "out.txt")
+    os.WriteFile(input, []byte("hello"), 0644)
+
+    var parser Parser
+    parser.Init("app", "")
+    parser.Option(Long("input"), Short('i'), File(FileRead))
+    parser.Option(Long("output"), Short('o'), File(FileWrite))
+    parser.Option(Long("log"), File(FileAppend), Metavar("LOG"))
+    parser.Option(Long("name"))
+    if parser.Usage() != "app [-i FILE] [-o FILE] [--log LOG] [--name VALUE] [ARGS...]" { t.Error(parser.Usage()) }
+
+    os.Args = []string{"app.exe", "-i", input, "-o", output, "--log", "-"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    in, err := results.File("input")
+    if err != nil { t.Fatal(err) }
+    data, _ := io.ReadAll(in)
+    if string(data) != "hello" { t.Error(string(data)) }
+    again, _ := results.File("input")
+    if again != in { t.Error() }
+    out, err := results.File("output")
+    if err != nil { t.Fatal(err) }
+    out.WriteString("done")
+    log, _ := results.File("log")
+    if log != os.Stdout { t.Error() }
+    _, err = results.File("name")
+    if err == nil || err.Error() != "invalid argument: name is not a file option" { t.Error(err) }
+    if results.Close() != nil { t.Error() }
+    data, _ = os.ReadFile(output)
+    if string(data) != "done" { t.Error(string(data)) }
+    _, err = in.Read(make([]byte, 1))
+    if err == nil { t.Error() }
+
+    os.Args = []string{"app.exe", "-i", "-"}
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    in, _ = results.File("input")
+    out, _ = results.File("output")
+    if in != os.Stdin || out != nil { t.Error() }
+
+    os.Args = []string{"app.exe", "-i", filepath.Join(dir, "missing")}
+    _, err = parser.Parse()
+    if err == nil || !strings.HasSuffix(err.(*ParseError).Message, "(file does not exist)") { t.Error(err) }
+    os.Args = []string{"app.exe", "-o", filepath.Join(dir, "missing", "out")}
+    _, err = parser.Parse()
+    if err == nil || !strings.HasSuffix(err.(*ParseError).Message, "(directory does not exist)") { t.Error(err) }
+    os.Args = []string{"app.exe", "-o", dir}
+    _, err = parser.Parse()
+    if err == nil || !strings.HasSuffix(err.(*ParseError).Message, "(is a directory)") { t.Error(err) }
+    os.Args = []string{"app.exe", "-i", filepath.Join(input, "x")}
+    _, err = parser.Parse()
+    if err == nil || !strings.HasSuffix(err.(*ParseError).Message, "(not a directory)") { t.Error(err) }
+}
+
+func TestFilePermissions(t *testing.T) {
+    if os.Geteuid() == 0 {
+        t.Skip("permissions aren't checked for root")
+    }
+    dir := t.TempDir()
+    locked := filepath.Join(dir, "locked")
+    os.Mkdir(locked, 0755)
+    os.WriteFile(filepath.Join(locked, "in.txt"), []byte("hello"), 0644)
+    os.Chmod(locked, 0)
+    defer os.Chmod(locked, 0755)
+
+    var parser Parser
+    parser.Init("app", "")
+    parser.Option(Long("input"), File(FileRead))
+    parser.Option(Long("output"), File(FileWrite))
+    os.Args = []string{"app.exe", "--input", filepath.Join(locked, "in.txt")}
+    _, err := parser.Parse()
+    if err == nil || !strings.HasSuffix(err.(*ParseError).Message, "(permission denied)") { t.Error(err) }
+    os.Args = []string{"app.exe", "--output", filepath.Join(locked, "out.txt")}
+    _, err = parser.Parse()
+    if err == nil || !strings.HasSuffix(err.(*ParseError).Message, "(permission denied)") { t.Error(err) }
+}
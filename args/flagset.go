@@ -0,0 +1,69 @@
+package args
+
+import (
+    "errors"
+    "flag"
+    "fmt"
+    "strconv"
+)
+
+// Reports whether a `flag.Flag` is a boolean flag, like the `flag` package
+// does
+func isBoolValue(f *flag.Flag) bool {
+    b, isBool := f.Value.(interface{ IsBoolFlag() bool })
+    return isBool && b.IsBoolFlag()
+}
+
+/// Add the flags of a `flag.FlagSet`. Boolean flags are added as flags and
+/// the others as options. Both keep the flag's default value. Boolean flags
+/// accept a value in every syntax, like `--name=false`. Names of one
+/// character become short names and the others long names. `SyntaxSingleDash`
+/// keeps the syntax of the `flag` package
+/// @param fs flag set to import
+/// @return An error if a name already exists
+func (ap *Parser) ImportFlagSet(fs *flag.FlagSet) error {
+    var err error
+    fs.VisitAll(func(f *flag.Flag) {
+        if err != nil { return }
+        opts := []ArgOption{Help(f.Usage)}
+        if len([]rune(f.Name)) == 1 {
+            opts = append(opts, ShortName(f.Name))
+        }else {
+            opts = append(opts, Long(f.Name))
+        }
+        if isBoolValue(f) {
+            err = ap.Flag(opts...)
+            if err == nil {
+                fl := ap.flags[f.Name]
+                fl.DefaultsTo, _ = strconv.ParseBool(f.DefValue)
+                fl.TakesValue = true
+                ap.flags[f.Name] = fl
+            }
+        }else {
+            err = ap.Option(append(opts, Default(f.DefValue))...)
+        }
+    })
+
+    return err
+}
+
+/// Write the values of the flags and options that were set to the flags of a
+/// `flag.FlagSet` with the same names, through `flag.Value.Set`
+/// @param fs flag set to update
+/// @return An error if a flag rejects its value
+func (r *Results) ApplyToFlagSet(fs *flag.FlagSet) error {
+    var err error
+    fs.VisitAll(func(f *flag.Flag) {
+        if err != nil || !r.IsSet(f.Name) { return }
+        val := r.Option[f.Name]
+        if isBoolValue(f) {
+            val = strconv.FormatBool(r.Flag[f.Name])
+        }
+        setErr := fs.Set(f.Name, val)
+        if setErr != nil {
+            err = errors.New(fmt.Sprintf("invalid value: %s -> %s (%s)", f.Name, val, setErr))
+        }
+    })
+
+    return err
+}
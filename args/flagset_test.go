@@ -0,0 +1,100 @@
+package args
+
+import (
+    "bytes"
+    "flag"
+    "os"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestFlagSet(t *testing.T) {
+    fs := flag.NewFlagSet("app", flag.ContinueOnError)
+    verbose := fs.Bool("verbose", false, "Verbose output")
+    quiet := fs.Bool("q", false, "Quiet")
+    count := fs.Int("count", 3, "Number of runs")
+    timeout := fs.Duration("timeout", time.Second, "Timeout")
+
+    var parser Parser
+    parser.Init("app", "")
+    err := parser.ImportFlagSet(fs)
+    if err != nil { t.Fatal(err) }
+    if !parser.isFlag("verbose") || !parser.isFlag("q") || !parser.isOption("count") { t.Error() }
+    if parser.options["count"].DefaultsTo != "3" || parser.options["count"].Help != "Number of runs" { t.Error() }
+    if parser.Usage() != "app [-q] [--verbose] [--count VALUE] [--timeout VALUE] [ARGS...]" { t.Error(parser.Usage()) }
+
+    os.Args = []string{"app.exe", "--verbose", "-q", "--count=5"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    err = results.ApplyToFlagSet(fs)
+    if err != nil { t.Fatal(err) }
+    if !*verbose || !*quiet || *count != 5 || *timeout != time.Second { t.Error() }
+
+    os.Args = []string{"app.exe", "--timeout", "soon"}
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    err = results.ApplyToFlagSet(fs)
+    if err == nil { t.Error() }
+
+    err = parser.ImportFlagSet(fs)
+    if err == nil || err.Error() != "duplicate argument: count" { t.Error(err) }
+}
+
+func TestFlagSetBoolDefault(t *testing.T) {
+    fs := flag.NewFlagSet("app", flag.ContinueOnError)
+    color := fs.Bool("color", true, "Color the output")
+    verbose := fs.Bool("v", false, "Verbose output")
+
+    var parser Parser
+    parser.Init("app", "")
+    parser.Syntax = SyntaxSingleDash
+    err := parser.ImportFlagSet(fs)
+    if err != nil { t.Fatal(err) }
+    os.Args = []string{"app.exe", "-v"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if !results.Flag["color"] || !results.Flag["v"] { t.Error() }
+    err = results.ApplyToFlagSet(fs)
+    if err != nil || !*color || !*verbose { t.Error(err) }
+
+    os.Args = []string{"app.exe", "-color=false"}
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Flag["color"] { t.Error() }
+    err = results.ApplyToFlagSet(fs)
+    if err != nil || *color { t.Error(err) }
+}
+
+func TestFlagSetBoolValue(t *testing.T) {
+    fs := flag.NewFlagSet("app", flag.ContinueOnError)
+    fs.Bool("color", true, "Color the output")
+
+    var parser Parser
+    parser.Init("app", "")
+    parser.Width = 80
+    parser.Colors = ColorsNever
+    parser.Flag(Long("verbose"))
+    err := parser.ImportFlagSet(fs)
+    if err != nil { t.Fatal(err) }
+    os.Args = []string{"app.exe", "--color=false"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Flag["color"] { t.Error() }
+    os.Args = []string{"app.exe", "--verbose=false"}
+    _, err = parser.Parse()
+    if err == nil { t.Error() }
+    var buf bytes.Buffer
+    parser.WriteHelp(&buf)
+    if !strings.Contains(buf.String(), "--color [default: true]\n        Color the output\n") { t.Error(buf.String()) }
+
+    var restored Parser
+    err = restored.InitFromSchema(parser.Schema())
+    if err != nil { t.Fatal(err) }
+    os.Args = []string{"app.exe", "--color=false"}
+    results, err = restored.Parse()
+    if err != nil || results.Flag["color"] { t.Error(err) }
+    os.Args = []string{"app.exe"}
+    results, err = restored.Parse()
+    if err != nil || !results.Flag["color"] { t.Error(err) }
+}
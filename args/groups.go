@@ -0,0 +1,133 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+)
+
+type group struct {
+    Description string
+    Hidden bool
+    args []string
+}
+
+/// Add a group of flags and options. Groups get their own section in the
+/// help message, man page and documentation, after the ungrouped flags and
+/// options
+/// @param name group's name, used as the section's header
+/// @param description group's description
+/// @return An error if the group already exists
+func (ap *Parser) AddGroup(name string, description string) error {
+    if name == "" {
+        return errors.New("invalid argument: missing name")
+    }
+    _, found := ap.groups[name]
+    if found {
+        return errors.New(fmt.Sprintf("duplicate argument: group %s", name))
+    }
+    ap.groups[name] = group{Description: description, args: []string{}}
+    ap.groupsOrder = append(ap.groupsOrder, name)
+
+    return nil
+}
+
+/// Move flags or options to a group. They are displayed in the order they
+/// were added to the group
+/// @param group group's name
+/// @param names flags' or options' names
+/// @return An error if the group or an argument doesn't exist
+func (ap *Parser) SetGroup(group string, names ...string) error {
+    _, found := ap.groups[group]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: group %s does not exist", group))
+    }
+    for _, name := range names {
+        if !ap.isFlag(name) && !ap.isOption(name) {
+            return errors.New(fmt.Sprintf("invalid argument: %s does not exist", name))
+        }
+    }
+    for _, name := range names {
+        ap.addToGroup(name, group)
+    }
+
+    return nil
+}
+
+/// Hide groups and their flags and options from the `Help` function. They are
+/// still parsed
+/// @param names groups' names
+/// @return An error if a group doesn't exist
+func (ap *Parser) HideGroup(names ...string) error {
+    for _, name := range names {
+        g, found := ap.groups[name]
+        if !found {
+            return errors.New(fmt.Sprintf("invalid argument: group %s does not exist", name))
+        }
+        g.Hidden = true
+        ap.groups[name] = g
+    }
+
+    return nil
+}
+
+/// Change the order groups are displayed in. The given groups are displayed
+/// first, the others keep the order they were added in
+/// @param names groups' names
+/// @return An error if a group doesn't exist
+func (ap *Parser) SetGroupOrder(names ...string) error {
+    order := []string{}
+    listed := map[string]bool{}
+    for _, name := range names {
+        _, found := ap.groups[name]
+        if !found {
+            return errors.New(fmt.Sprintf("invalid argument: group %s does not exist", name))
+        }
+        if listed[name] { continue }
+        listed[name] = true
+        order = append(order, name)
+    }
+    for _, name := range ap.groupsOrder {
+        if !listed[name] { order = append(order, name) }
+    }
+    ap.groupsOrder = order
+
+    return nil
+}
+
+func (ap *Parser) addToGroup(name string, group string) {
+    previous, found := ap.argGroup[name]
+    if found {
+        g := ap.groups[previous]
+        args := []string{}
+        for _, a := range g.args {
+            if a != name { args = append(args, a) }
+        }
+        g.args = args
+        ap.groups[previous] = g
+    }
+    g := ap.groups[group]
+    g.args = append(g.args, name)
+    ap.groups[group] = g
+    ap.argGroup[name] = group
+}
+
+// Flags and options that aren't in a group, in the order they were added
+func (ap *Parser) ungrouped(names []string) []string {
+    args := []string{}
+    for _, k := range names {
+        _, grouped := ap.argGroup[k]
+        if !grouped { args = append(args, k) }
+    }
+
+    return args
+}
+
+// Groups displayed by the `Help` function, in order
+func (ap *Parser) visibleGroups() []string {
+    groups := []string{}
+    for _, name := range ap.groupsOrder {
+        if !ap.groups[name].Hidden { groups = append(groups, name) }
+    }
+
+    return groups
+}
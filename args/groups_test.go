@@ -0,0 +1,79 @@
+package args
+
+import (
+    "bytes"
+    "encoding/json"
+    "os"
+    "reflect"
+    "strings"
+    "testing"
+)
+
+func TestGroups(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Width = 80
+    parser.Colors = ColorsNever
+    parser.UsageHelpMsg = ""
+    parser.AddGroup("NETWORK", "Connection settings")
+    parser.AddGroup("DEBUGGING", "")
+    parser.Flag(Long("verbose"), Help("Verbose"))
+    parser.Option(Long("host"), Help("Host"), Group("NETWORK"))
+    parser.Flag(Long("ipv6"), Help("Use IPv6"), Group("NETWORK"))
+    parser.Flag(Long("trace"), Help("Trace"))
+    parser.SetGroup("DEBUGGING", "trace")
+    var buf bytes.Buffer
+    parser.WriteHelp(&buf)
+    expected := "app\n\nFLAGS\n    --verbose\n        Verbose\n\n" +
+        "NETWORK\n    Connection settings\n\n" +
+        "    --host\n        Host\n\n    --ipv6\n        Use IPv6\n\n" +
+        "DEBUGGING\n    --trace\n        Trace\n\n"
+    if buf.String() != expected { t.Error(buf.String()) }
+
+    parser.SetGroupOrder("DEBUGGING")
+    if parser.groupsOrder[0] != "DEBUGGING" || parser.groupsOrder[1] != "NETWORK" { t.Error() }
+    parser.HideGroup("NETWORK")
+    buf.Reset()
+    parser.WriteHelp(&buf)
+    if strings.Contains(buf.String(), "host") { t.Error(buf.String()) }
+    if parser.Usage() != "app [--verbose] [--trace] [ARGS...]" { t.Error(parser.Usage()) }
+    os.Args = []string{"app.exe", "--host", "h", "--ipv6"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Option["host"] != "h" || !results.Flag["ipv6"] { t.Error() }
+
+    if parser.Flag(Long("x"), Group("missing")) == nil { t.Error() }
+    if parser.AddGroup("NETWORK", "") == nil { t.Error() }
+    if parser.SetGroup("NETWORK", "missing") == nil { t.Error() }
+    if parser.HideGroup("missing") == nil { t.Error() }
+    if parser.SetGroupOrder("missing") == nil { t.Error() }
+}
+
+func TestGroupsDocs(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.AddGroup("NETWORK", "Connection settings")
+    parser.AddGroup("DEBUGGING", "")
+    parser.Option(Long("host"), Group("NETWORK"))
+    parser.Flag(Long("ipv6"), Group("NETWORK"))
+    parser.Flag(Long("trace"), Group("DEBUGGING"))
+    var buf bytes.Buffer
+    parser.ManPage(&buf, 1)
+    if !strings.Contains(buf.String(), ".SH NETWORK\nConnection settings\n.TP\n\\fB\\-\\-host\\fR") { t.Error(buf.String()) }
+    buf.Reset()
+    parser.Markdown(&buf)
+    if !strings.Contains(buf.String(), "## NETWORK\n\nConnection settings\n\n<a id=\"option-host\"></a>") { t.Error(buf.String()) }
+    if !strings.Contains(buf.String(), "<a id=\"flag-ipv6\"></a>") { t.Error(buf.String()) }
+    buf.Reset()
+    parser.HTML(&buf)
+    if !strings.Contains(buf.String(), "<h2>NETWORK</h2>\n<p>Connection settings</p>\n<dl>\n") { t.Error(buf.String()) }
+
+    parser.HideGroup("DEBUGGING")
+    data, err := json.Marshal(&parser)
+    if err != nil { t.Fatal(err) }
+    var restored Parser
+    err = json.Unmarshal(data, &restored)
+    if err != nil { t.Fatal(err) }
+    if !reflect.DeepEqual(restored.Schema().Groups, parser.Schema().Groups) { t.Error(restored.Schema().Groups) }
+    if !restored.isHidden("trace") || restored.argGroup["ipv6"] != "NETWORK" { t.Error() }
+}
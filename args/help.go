@@ -0,0 +1,408 @@
+package args
+
+import (
+    "fmt"
+    "io"
+    "os"
+    "strconv"
+    "strings"
+    "unicode"
+)
+
+type helpEntry struct {
+    names string
+    extra string
+    help string
+    choices [][2]string
+    nameColor ANSICode
+    descColor ANSICode
+}
+
+// Number of columns taken by a rune in a terminal
+func runeWidth(r rune) int {
+    if r == 0 || unicode.In(r, unicode.Mn, unicode.Me, unicode.Cf) {
+        return 0
+    }
+    if (r >= 0x1100 && r <= 0x115F) ||
+        (r >= 0x2E80 && r <= 0xA4CF && r != 0x303F) ||
+        (r >= 0xAC00 && r <= 0xD7A3) ||
+        (r >= 0xF900 && r <= 0xFAFF) ||
+        (r >= 0xFE30 && r <= 0xFE4F) ||
+        (r >= 0xFF00 && r <= 0xFF60) ||
+        (r >= 0xFFE0 && r <= 0xFFE6) ||
+        (r >= 0x1F300 && r <= 0x1F64F) ||
+        (r >= 0x1F900 && r <= 0x1F9FF) ||
+        (r >= 0x20000 && r <= 0x3FFFD) {
+        return 2
+    }
+
+    return 1
+}
+
+// Number of columns taken by a string in a terminal
+func displayWidth(text string) int {
+    width := 0
+    for _, r := range text {
+        width += runeWidth(r)
+    }
+
+    return width
+}
+
+// Splits `text` into lines no wider than `width` columns. Explicit line
+// breaks are kept and words wider than `width` get a line of their own
+func wrapText(text string, width int) []string {
+    lines := []string{}
+    for _, paragraph := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+        words := strings.Fields(paragraph)
+        if len(words) == 0 {
+            lines = append(lines, "")
+            continue
+        }
+        line := words[0]
+        lineWidth := displayWidth(line)
+        for _, word := range words[1:] {
+            wordWidth := displayWidth(word)
+            if width > 0 && lineWidth + 1 + wordWidth > width {
+                lines = append(lines, line)
+                line = word
+                lineWidth = wordWidth
+            }else {
+                line += " " + word
+                lineWidth += 1 + wordWidth
+            }
+        }
+        lines = append(lines, line)
+    }
+
+    return lines
+}
+
+// Width of the terminal, read from `COLUMNS` or the terminal itself. Defaults
+// to 80 columns
+func terminalWidth() int {
+    columns, err := strconv.Atoi(os.Getenv("COLUMNS"))
+    if err == nil && columns > 0 {
+        return columns
+    }
+    columns = ioctlWidth(os.Stdout)
+    if columns > 0 {
+        return columns
+    }
+
+    return 80
+}
+
+// Width of the help of the parser and its commands
+func (ap *Parser) helpWidth() int {
+    if ap.root().Width > 0 {
+        return ap.root().Width
+    }
+
+    return terminalWidth()
+}
+
+func (ap *Parser) writeHeader(w io.Writer, header string) {
+    if header != "" {
+        ap.colored(w, ap.theme().Header, header)
+        fmt.Fprintln(w)
+    }
+}
+
+// Writes the entries of a section one below the other, with the descriptions
+// indented under the names
+func (ap *Parser) writeEntries(w io.Writer, width int, entries []helpEntry) {
+    indent := "        "
+    for _, e := range entries {
+        fmt.Fprint(w, "    ")
+        ap.colored(w, e.nameColor, e.names)
+        if e.extra != "" {
+            fmt.Fprint(w, " ")
+            ap.colored(w, ap.theme().OptionAllowed, e.extra)
+        }
+        fmt.Fprintln(w)
+        if e.help != "" {
+            for _, line := range wrapText(e.help, width - len(indent)) {
+                fmt.Fprint(w, indent)
+                ap.colored(w, e.descColor, line)
+                fmt.Fprintln(w)
+            }
+        }
+        ap.writeChoices(w, width, indent + "    ", e.choices, e.descColor)
+        fmt.Fprintln(w)
+    }
+}
+
+// Writes the entries of a section with the names and descriptions in two
+// aligned columns
+func (ap *Parser) writeColumns(w io.Writer, width int, entries []helpEntry) {
+    column := 0
+    for _, e := range entries {
+        nameWidth := displayWidth(e.names)
+        if e.extra != "" { nameWidth += 1 + displayWidth(e.extra) }
+        if nameWidth > column { column = nameWidth }
+    }
+    if column > width / 3 { column = width / 3 }
+    indent := strings.Repeat(" ", 4 + column + 2)
+    for _, e := range entries {
+        fmt.Fprint(w, "    ")
+        ap.colored(w, e.nameColor, e.names)
+        nameWidth := displayWidth(e.names)
+        if e.extra != "" {
+            fmt.Fprint(w, " ")
+            ap.colored(w, ap.theme().OptionAllowed, e.extra)
+            nameWidth += 1 + displayWidth(e.extra)
+        }
+        lines := []string{}
+        if e.help != "" {
+            lines = wrapText(e.help, width - len(indent))
+        }
+        if len(lines) != 0 && nameWidth <= column {
+            fmt.Fprint(w, strings.Repeat(" ", column - nameWidth + 2))
+            ap.colored(w, e.descColor, lines[0])
+            lines = lines[1:]
+        }
+        fmt.Fprintln(w)
+        for _, line := range lines {
+            fmt.Fprint(w, indent)
+            ap.colored(w, e.descColor, line)
+            fmt.Fprintln(w)
+        }
+        ap.writeChoices(w, width, indent + "  ", e.choices, e.descColor)
+    }
+    fmt.Fprintln(w)
+}
+
+func (ap *Parser) writeChoices(
+    w io.Writer, width int, indent string, choices [][2]string, descColor ANSICode,
+) {
+    valueWidth := 0
+    for _, c := range choices {
+        if displayWidth(c[0]) > valueWidth { valueWidth = displayWidth(c[0]) }
+    }
+    descIndent := indent + strings.Repeat(" ", valueWidth + 2)
+    for _, c := range choices {
+        fmt.Fprint(w, indent)
+        ap.colored(w, ap.theme().OptionAllowed, c[0])
+        lines := wrapText(c[1], width - len(descIndent))
+        if len(lines) != 0 && lines[0] != "" {
+            fmt.Fprint(w, strings.Repeat(" ", valueWidth - displayWidth(c[0]) + 2))
+            ap.colored(w, descColor, lines[0])
+            for _, line := range lines[1:] {
+                fmt.Fprintln(w)
+                fmt.Fprint(w, descIndent)
+                ap.colored(w, descColor, line)
+            }
+        }
+        fmt.Fprintln(w)
+    }
+}
+
+func (ap *Parser) writeSection(
+    w io.Writer, width int, header string, description string, entries []helpEntry,
+) {
+    if len(entries) == 0 {
+        return
+    }
+    ap.writeHeader(w, header)
+    if description != "" {
+        for _, line := range wrapText(description, width - 4) {
+            fmt.Fprint(w, "    ")
+            ap.colored(w, ap.theme().Description, line)
+            fmt.Fprintln(w)
+        }
+        fmt.Fprintln(w)
+    }
+    if ap.root().TwoColumns {
+        ap.writeColumns(w, width, entries)
+    }else {
+        ap.writeEntries(w, width, entries)
+    }
+}
+
+func (ap *Parser) commandEntries() []helpEntry {
+    entries := []helpEntry{}
+    aliases := invertAliases(ap.commandAliases)
+    for _, k := range ap.commandsOrder {
+        cmd := ap.commands[k]
+        if cmd.Hidden { continue }
+        names := []string{k}
+        for _, a := range aliases[k] {
+            _, dep := ap.deprecatedCommands[a]
+            if !dep { names = append(names, a) }
+        }
+        e := helpEntry{
+            names: strings.Join(names, ", "), help: cmd.Help,
+            nameColor: ap.theme().Command, descColor: ap.theme().CommandDescription,
+        }
+        succ, dep := ap.deprecatedCommands[k]
+        if dep && succ != "" {
+            e.names += fmt.Sprintf(" (deprecated, use %s)", succ)
+        }else if dep {
+            e.names += " (deprecated)"
+        }
+        entries = append(entries, e)
+    }
+
+    return entries
+}
+
+func (ap *Parser) flagEntry(k string) helpEntry {
+    e := helpEntry{
+        names: strings.Join(ap.argNames(k), ", ") + ap.deprecationHint(k),
+        help: ap.flags[k].Help,
+        nameColor: ap.theme().Flag, descColor: ap.theme().FlagDescription,
+    }
+    extra := []string{}
+    if ap.flags[k].DefaultsTo {
+        extra = append(extra, "[default: true]")
+    }
+    variable, found := ap.env[k]
+    if found {
+        extra = append(extra, fmt.Sprintf("[env: %s]", variable))
+    }
+    e.extra = strings.Join(extra, " ")
+
+    return e
+}
+
+func (ap *Parser) optionEntry(k string) helpEntry {
+    op := ap.options[k]
+    names := strings.Join(ap.argNames(k), ", ")
+    if op.Metavar != "" {
+        names = ap.withMetavar(names, op.Metavar)
+    }
+    extra := []string{}
+    if len(op.Allowed) != 0 {
+        extra = append(extra, strings.Join(op.Allowed, "|"))
+    }
+    if len(op.Choices) != 0 {
+        values := []string{}
+        for _, c := range op.Choices {
+            values = append(values, c.Value)
+        }
+        extra = append(extra, strings.Join(values, "|"))
+    }
+    for _, v := range op.Validators {
+        if v.Description != "" {
+            extra = append(extra, fmt.Sprintf("(%s)", v.Description))
+        }
+    }
+    if ap.shownDefault(k) != "" {
+        extra = append(extra, fmt.Sprintf("[default: %s]", ap.shownDefault(k)))
+    }
+    variable, found := ap.env[k]
+    if found {
+        extra = append(extra, fmt.Sprintf("[env: %s]", variable))
+    }
+    e := helpEntry{
+        names: names + ap.deprecationHint(k),
+        extra: strings.Join(extra, " "),
+        help: op.Help,
+        nameColor: ap.theme().Option, descColor: ap.theme().OptionDescription,
+    }
+    for _, c := range op.Choices {
+        if c.Help == "" && len(c.Aliases) == 0 { continue }
+        text := c.Help
+        if len(c.Aliases) != 0 {
+            text = strings.TrimSpace(fmt.Sprintf("%s (%s)", text, strings.Join(c.Aliases, ", ")))
+        }
+        e.choices = append(e.choices, [2]string{c.Value, text})
+    }
+
+    return e
+}
+
+// Entries of the visible flags and options in `names`
+func (ap *Parser) argEntries(names []string) []helpEntry {
+    entries := []helpEntry{}
+    for _, k := range names {
+        if ap.isHidden(k) { continue }
+        if ap.isFlag(k) {
+            entries = append(entries, ap.flagEntry(k))
+        }else {
+            entries = append(entries, ap.optionEntry(k))
+        }
+    }
+
+    return entries
+}
+
+// Flags and options in `names` that aren't persistent
+func (ap *Parser) local(names []string) []string {
+    args := []string{}
+    for _, k := range names {
+        if !ap.isPersistent(k) { args = append(args, k) }
+    }
+
+    return args
+}
+
+// Entries of the persistent flags and options of the parser and its parents,
+// starting with the top level parser
+func (ap *Parser) globalEntries() []helpEntry {
+    levels := []*Parser{}
+    for p := ap; p != nil; p = p.parent {
+        levels = append([]*Parser{p}, levels...)
+    }
+    entries := []helpEntry{}
+    for _, p := range levels {
+        names := []string{}
+        for _, k := range append(append([]string{}, p.flagsOrder...), p.optionsOrder...) {
+            if p.isPersistent(k) && (p == ap || (!ap.isFlag(k) && !ap.isOption(k))) {
+                names = append(names, k)
+            }
+        }
+        entries = append(entries, p.argEntries(names)...)
+    }
+
+    return entries
+}
+
+/// Write the help message
+/// @param w where the help message is written
+func (ap *Parser) WriteHelp(w io.Writer) {
+    width := ap.helpWidth()
+    if ap.name != "" || ap.description != "" {
+        ap.colored(w, ap.theme().Title, ap.name)
+        if ap.description != "" {
+            prefix := " - "
+            if ap.name == "" { prefix = "" }
+            indent := strings.Repeat(" ", displayWidth(ap.name + prefix))
+            lines := wrapText(ap.description, width - len(indent))
+            ap.colored(w, ap.theme().Description, prefix + lines[0])
+            for _, line := range lines[1:] {
+                fmt.Fprintln(w)
+                if line != "" { fmt.Fprint(w, indent) }
+                ap.colored(w, ap.theme().Description, line)
+            }
+        }
+        fmt.Fprintln(w)
+        fmt.Fprintln(w)
+    }
+
+    if ap.UsageHelpMsg != "" {
+        ap.writeHeader(w, ap.UsageHelpMsg)
+        indent := strings.Repeat(" ", 4 + displayWidth(ap.name) + 1)
+        lines := wrapText(ap.Usage(), width - len(indent))
+        fmt.Fprintf(w, "    %s\n", lines[0])
+        for _, line := range lines[1:] {
+            fmt.Fprintf(w, "%s%s\n", indent, line)
+        }
+        fmt.Fprintln(w)
+    }
+
+    ap.writeSection(w, width, ap.CommandsHelpMsg, "", ap.commandEntries())
+    ap.writeSection(w, width, ap.FlagsHelpMsg, "", ap.argEntries(ap.local(ap.ungrouped(ap.flagsOrder))))
+    ap.writeSection(w, width, ap.OptionsHelpMsg, "", ap.argEntries(ap.local(ap.ungrouped(ap.optionsOrder))))
+    for _, name := range ap.visibleGroups() {
+        g := ap.groups[name]
+        ap.writeSection(w, width, name, g.Description, ap.argEntries(ap.local(g.args)))
+    }
+    ap.writeSection(w, width, ap.GlobalOptionsHelpMsg, "", ap.globalEntries())
+}
+
+/// Display the help message
+func (ap *Parser) Help() {
+    ap.WriteHelp(os.Stdout)
+}
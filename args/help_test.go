@@ -0,0 +1,67 @@
+package args
+
+import (
+    "bytes"
+    "os"
+    "strings"
+    "testing"
+)
+
+func TestWrapText(t *testing.T) {
+    lines := wrapText("the quick brown fox jumps", 10)
+    if strings.Join(lines, "|") != "the quick|brown fox|jumps" { t.Error(lines) }
+    lines = wrapText("a\n\nbb", 10)
+    if strings.Join(lines, "|") != "a||bb" { t.Error(lines) }
+    lines = wrapText("short averyveryverylongword", 5)
+    if strings.Join(lines, "|") != "short|averyveryverylongword" { t.Error(lines) }
+    if displayWidth("日本") != 4 || displayWidth("é") != 1 { t.Error() }
+    lines = wrapText("日本語 日本語", 8)
+    if len(lines) != 2 { t.Error(lines) }
+}
+
+func TestWriteHelp(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "A tool with a description that is long enough to wrap")
+    parser.Width = 40
+    parser.Colors = ColorsNever
+    parser.UsageHelpMsg = ""
+    parser.Flag(Long("verbose"), Short('v'), Help("Print every step of the process to the terminal"))
+    parser.Option(Long("mode"), Choices(Choice{Value: "fast", Help: "Go fast"}))
+
+    var buf bytes.Buffer
+    parser.WriteHelp(&buf)
+    expected := "app - A tool with a description that is\n" +
+        "      long enough to wrap\n\n" +
+        "FLAGS\n" +
+        "    --verbose, -v\n" +
+        "        Print every step of the process\n" +
+        "        to the terminal\n\n" +
+        "OPTIONS\n" +
+        "    --mode fast\n" +
+        "            fast  Go fast\n\n"
+    if buf.String() != expected { t.Error(buf.String()) }
+
+    buf.Reset()
+    parser.TwoColumns = true
+    parser.WriteHelp(&buf)
+    expected = "app - A tool with a description that is\n" +
+        "      long enough to wrap\n\n" +
+        "FLAGS\n" +
+        "    --verbose, -v  Print every step of\n" +
+        "                   the process to the\n" +
+        "                   terminal\n\n" +
+        "OPTIONS\n" +
+        "    --mode fast\n" +
+        "                   fast  Go fast\n\n"
+    if buf.String() != expected { t.Error(buf.String()) }
+}
+
+func TestHelpWidth(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    os.Setenv("COLUMNS", "100")
+    defer os.Unsetenv("COLUMNS")
+    if parser.helpWidth() != 100 { t.Error() }
+    parser.Width = 60
+    if parser.helpWidth() != 60 { t.Error() }
+}
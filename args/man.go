@@ -0,0 +1,249 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "path/filepath"
+    "strings"
+)
+
+func roffEscape(text string) string {
+    text = strings.ReplaceAll(text, "\\", "\\e")
+    text = strings.ReplaceAll(text, "-", "\\-")
+    lines := strings.Split(text, "\n")
+    for i, line := range lines {
+        if strings.HasPrefix(line, ".") || strings.HasPrefix(line, "'") {
+            lines[i] = "\\&" + line
+        }
+    }
+
+    return strings.Join(lines, "\n")
+}
+
+// Writes `text` as roff paragraphs. Empty lines start a new paragraph
+func roffParagraphs(b *strings.Builder, text string) {
+    for _, line := range strings.Split(strings.TrimRight(text, "\n"), "\n") {
+        if strings.TrimSpace(line) == "" {
+            b.WriteString(".PP\n")
+        }else {
+            b.WriteString(roffEscape(line) + "\n")
+        }
+    }
+}
+
+// Name used in the man page's title and file name
+func manName(name string) string {
+    return strings.Join(strings.Fields(name), "-")
+}
+
+func (ap *Parser) roffArgNames(name string) string {
+    names := ap.argNames(name)
+    for i, n := range names {
+        names[i] = "\\fB" + roffEscape(n) + "\\fR"
+    }
+
+    return strings.Join(names, ", ")
+}
+
+// Writes the values accepted by an option, or its metavar, and the
+// validators' descriptions after the option's names
+func (ap *Parser) roffOptionValues(b *strings.Builder, name string) {
+    op := ap.options[name]
+    values := []string{}
+    for _, a := range op.Allowed {
+        values = append(values, "\\fI" + roffEscape(a) + "\\fR")
+    }
+    for _, c := range op.Choices {
+        values = append(values, "\\fI" + roffEscape(c.Value) + "\\fR")
+    }
+    if len(values) != 0 {
+        b.WriteString(" " + strings.Join(values, "|"))
+    }else {
+        b.WriteString(" \\fI" + roffEscape(ap.metavar(name)) + "\\fR")
+    }
+    for _, v := range op.Validators {
+        if v.Description != "" {
+            b.WriteString(" (" + roffEscape(v.Description) + ")")
+        }
+    }
+}
+
+// Writes the visible flags and options in `names`
+func (ap *Parser) roffArgs(b *strings.Builder, names []string) {
+    for _, k := range names {
+        if ap.isHidden(k) { continue }
+        b.WriteString(".TP\n")
+        b.WriteString(ap.roffArgNames(k))
+        if ap.isFlag(k) {
+            b.WriteString(roffEscape(ap.deprecationHint(k)) + "\n")
+            if ap.flags[k].Help != "" {
+                roffParagraphs(b, ap.flags[k].Help)
+            }
+            continue
+        }
+        op := ap.options[k]
+        ap.roffOptionValues(b, k)
+        b.WriteString(roffEscape(ap.deprecationHint(k)) + "\n")
+        if op.Help != "" {
+            roffParagraphs(b, op.Help)
+        }
+        for _, c := range op.Choices {
+            if c.Help == "" { continue }
+            b.WriteString(".RS\n.TP\n")
+            b.WriteString("\\fI" + roffEscape(c.Value) + "\\fR\n")
+            roffParagraphs(b, c.Help)
+            b.WriteString(".RE\n")
+        }
+    }
+}
+
+// Writes the man page of a parser returned by `view`
+func (ap *Parser) writeManPage(w io.Writer, section int) error {
+    var b strings.Builder
+    name := ap.name
+    description := ap.description
+
+    fmt.Fprintf(&b, ".TH \"%s\" \"%d\" \"\" \"\" \"\"\n", strings.ToUpper(manName(name)), section)
+    b.WriteString(".SH NAME\n")
+    b.WriteString(roffEscape(manName(name)))
+    if description != "" {
+        summary := strings.SplitN(description, "\n", 2)[0]
+        b.WriteString(" \\- " + roffEscape(summary))
+    }
+    b.WriteString("\n")
+
+    b.WriteString(".SH SYNOPSIS\n")
+    b.WriteString(".B " + roffEscape(name) + "\n")
+    synopsis := strings.TrimPrefix(ap.usage(), ap.name)
+    b.WriteString(roffEscape(strings.TrimSpace(synopsis)) + "\n")
+
+    if description != "" {
+        b.WriteString(".SH DESCRIPTION\n")
+        roffParagraphs(&b, description)
+    }
+
+    if len(ap.commands) != 0 {
+        b.WriteString(".SH COMMANDS\n")
+        aliases := invertAliases(ap.commandAliases)
+        for _, k := range ap.commandsOrder {
+            c := ap.commands[k]
+            if c.Hidden { continue }
+            b.WriteString(".TP\n")
+            names := []string{"\\fB" + roffEscape(k) + "\\fR"}
+            for _, a := range aliases[k] {
+                _, dep := ap.deprecatedCommands[a]
+                if !dep { names = append(names, "\\fB" + roffEscape(a) + "\\fR") }
+            }
+            b.WriteString(strings.Join(names, ", "))
+            succ, dep := ap.deprecatedCommands[k]
+            if dep && succ != "" {
+                b.WriteString(" (deprecated, use " + roffEscape(succ) + ")")
+            }else if dep {
+                b.WriteString(" (deprecated)")
+            }
+            b.WriteString("\n")
+            if c.Help != "" {
+                roffParagraphs(&b, c.Help)
+            }
+            if ap.name != "" {
+                fmt.Fprintf(&b, "See \\fB%s\\fR(%d).\n", roffEscape(manName(ap.name + " " + k)), section)
+            }
+        }
+    }
+
+    args := ap.ungrouped(append(append([]string{}, ap.flagsOrder...), ap.optionsOrder...))
+    if len(args) != 0 {
+        b.WriteString(".SH OPTIONS\n")
+        ap.roffArgs(&b, args)
+    }
+    for _, name := range ap.visibleGroups() {
+        g := ap.groups[name]
+        if len(g.args) == 0 { continue }
+        b.WriteString(".SH " + roffEscape(strings.ToUpper(name)) + "\n")
+        if g.Description != "" {
+            roffParagraphs(&b, g.Description)
+        }
+        ap.roffArgs(&b, g.args)
+    }
+
+    bindings := ap.envBindings()
+    if len(bindings) != 0 {
+        b.WriteString(".SH ENVIRONMENT\n")
+        for _, bind := range bindings {
+            b.WriteString(".TP\n")
+            b.WriteString(".B " + roffEscape(bind[1]) + "\n")
+            fmt.Fprintf(&b, "Used as %s when it isn't given on the command line.\n", ap.roffArgNames(bind[0]))
+        }
+    }
+
+    _, err := io.WriteString(w, b.String())
+    return err
+}
+
+/// Write the man page in roff format
+/// @param w where the man page is written
+/// @param section manual section, usually 1
+/// @return An error if writing fails
+func (ap *Parser) ManPage(w io.Writer, section int) error {
+    return ap.view().writeManPage(w, section)
+}
+
+/// Write the man page of a command in roff format. The page lists the
+/// command's own arguments and the ones it inherits
+/// @param w where the man page is written
+/// @param section manual section, usually 1
+/// @param name command's name
+/// @return An error if the command doesn't exist or writing fails
+func (ap *Parser) CommandManPage(w io.Writer, section int, name string) error {
+    cmd := ap.Command(name)
+    if cmd == nil {
+        return errors.New(fmt.Sprintf("invalid argument: command %s does not exist", name))
+    }
+
+    return cmd.ManPage(w, section)
+}
+
+// Writes the man pages of the parser and of its visible commands, recursively
+func (ap *Parser) writeManPages(dir string, section int) error {
+    path := filepath.Join(dir, fmt.Sprintf("%s.%d", manName(ap.name), section))
+    file, err := os.Create(path)
+    if err != nil {
+        return err
+    }
+    err = ap.ManPage(file, section)
+    closeErr := file.Close()
+    if err != nil {
+        return err
+    }
+    if closeErr != nil {
+        return closeErr
+    }
+    for _, k := range ap.commandsOrder {
+        cmd := ap.commands[k]
+        if cmd.Hidden { continue }
+        err = cmd.parser.writeManPages(dir, section)
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
+
+/// Write the man pages of the parser and each of its commands, including
+/// nested commands, to a directory. Files are named after the parser and
+/// command, for example `app-run.1` and `app-remote-add.1`
+/// @param dir directory the man pages are written to. Created if it doesn't
+/// exist
+/// @param section manual section, usually 1
+/// @return An error if creating or writing a file fails
+func (ap *Parser) WriteManPages(dir string, section int) error {
+    err := os.MkdirAll(dir, 0755)
+    if err != nil {
+        return err
+    }
+
+    return ap.writeManPages(dir, section)
+}
@@ -0,0 +1,107 @@
+package args
+
+import (
+    "bytes"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestManPage(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "This is a test program\n\nIt does things")
+    parser.AddCommand("run", "Run something")
+    parser.AddCommand("debug", "")
+    parser.HideCommand("debug")
+    parser.AddFlag("verbose", "Print more", 'v')
+    parser.AddFlag("secret", "", '\000')
+    parser.Hide("secret")
+    parser.AddOption("mode", "Mode to use", 'm', "fast", []string{"fast", "slow"})
+    parser.Option(Long("token"), Help(".hidden-looking help"), Env("APP_TOKEN"))
+    var buf bytes.Buffer
+    if parser.ManPage(&buf, 1) != nil { t.Error() }
+    page := buf.String()
+    expected := []string{
+        ".TH \"APP\" \"1\" \"\" \"\" \"\"\n",
+        ".SH NAME\napp \\- This is a test program\n",
+        ".SH SYNOPSIS\n.B app\n",
+        ".SH DESCRIPTION\nThis is a test program\n.PP\nIt does things\n",
+        ".SH COMMANDS\n.TP\n\\fBrun\\fR\nRun something\nSee \\fBapp\\-run\\fR(1).\n",
+        ".SH OPTIONS\n.TP\n\\fB\\-\\-verbose\\fR, \\fB\\-v\\fR\nPrint more\n",
+        "\\fB\\-\\-mode\\fR, \\fB\\-m\\fR \\fIfast\\fR|\\fIslow\\fR\nMode to use\n",
+        "\\fB\\-\\-token\\fR \\fIVALUE\\fR\n\\&.hidden\\-looking help\n",
+        ".SH ENVIRONMENT\n.TP\n.B APP_TOKEN\n",
+    }
+    for _, e := range expected {
+        if !strings.Contains(page, e) { t.Error(e) }
+    }
+    if strings.Contains(page, "secret") || strings.Contains(page, "debug") { t.Error() }
+
+    buf.Reset()
+    if parser.CommandManPage(&buf, 1, "run") != nil { t.Error() }
+    if !strings.Contains(buf.String(), ".SH NAME\napp\\-run \\- Run something\n") { t.Error(buf.String()) }
+    if parser.CommandManPage(&buf, 1, "missing") == nil { t.Error() }
+}
+
+func TestManPageMetavar(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Option(Long("input"), Metavar("FILE"))
+    parser.Option(Long("define"), KeyValue(DuplicateLastWins))
+    var buf bytes.Buffer
+    parser.ManPage(&buf, 1)
+    if !strings.Contains(buf.String(), "\\fB\\-\\-input\\fR \\fIFILE\\fR\n") { t.Error(buf.String()) }
+    if !strings.Contains(buf.String(), "\\fB\\-\\-define\\fR \\fIKEY=VALUE\\fR\n") { t.Error(buf.String()) }
+}
+
+func TestWriteManPages(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.AddCommand("run", "Run something")
+    parser.AddCommand("debug", "")
+    parser.HideCommand("debug")
+    dir := filepath.Join(t.TempDir(), "man1")
+    if parser.WriteManPages(dir, 1) != nil { t.Error() }
+    entries, err := os.ReadDir(dir)
+    if err != nil { t.Fatal(err) }
+    if len(entries) != 2 { t.Fatal(entries) }
+    if entries[0].Name() != "app-run.1" || entries[1].Name() != "app.1" { t.Error() }
+}
+
+func TestCommandManPages(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.AddFlag("verbose", "Print more", 'v')
+    parser.Flag(Long("quiet"), Short('q'), Persistent())
+    parser.AddCommand("run", "Run something")
+    parser.AddCommand("version", "")
+    run := parser.Command("run")
+    run.AddFlag("fast", "Go fast", 'f')
+    run.AddCommand("job", "Run a job")
+    run.Command("job").AddOption("id", "Job id", '\000', "", []string{})
+    var buf bytes.Buffer
+    if parser.CommandManPage(&buf, 1, "run") != nil { t.Error() }
+    page := buf.String()
+    expected := []string{
+        ".SH SYNOPSIS\n.B app run\n[\\-f] [\\-q] [<command>] [ARGS...]\n",
+        ".SH COMMANDS\n.TP\n\\fBjob\\fR\nRun a job\nSee \\fBapp\\-run\\-job\\fR(1).\n",
+        ".SH OPTIONS\n.TP\n\\fB\\-\\-fast\\fR, \\fB\\-f\\fR\nGo fast\n.TP\n\\fB\\-\\-quiet\\fR, \\fB\\-q\\fR\n",
+    }
+    for _, e := range expected {
+        if !strings.Contains(page, e) { t.Error(e) }
+    }
+    if strings.Contains(page, "verbose") { t.Error(page) }
+    buf.Reset()
+    parser.CommandManPage(&buf, 1, "version")
+    if !strings.Contains(buf.String(), "\\fB\\-\\-verbose\\fR, \\fB\\-v\\fR\nPrint more\n") { t.Error(buf.String()) }
+
+    dir := t.TempDir()
+    if parser.WriteManPages(dir, 1) != nil { t.Error() }
+    entries, err := os.ReadDir(dir)
+    if err != nil { t.Fatal(err) }
+    if len(entries) != 4 || entries[0].Name() != "app-run-job.1" { t.Fatal(entries) }
+    data, err := os.ReadFile(filepath.Join(dir, "app-run-job.1"))
+    if err != nil { t.Fatal(err) }
+    if !strings.Contains(string(data), ".B app run job\n[\\-q] [\\-\\-id VALUE] [ARGS...]\n") { t.Error(string(data)) }
+}
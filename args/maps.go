@@ -0,0 +1,91 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+)
+
+type DuplicatePolicy int
+
+const (
+    /// The last value given for a key is kept
+    DuplicateLastWins DuplicatePolicy = iota
+    /// Giving a key more than once is an error
+    DuplicateError
+)
+
+/// Make an option a map option. Each value is a `key=value` pair stored in
+/// `Results.Map`. Values given on the command line replace the ones read
+/// from the environment variable
+/// @param name option's name
+/// @param duplicates what to do when a key is given more than once
+/// @param keyValidators validators checking the keys. The option's
+/// validators, allowed values and choices check the values
+/// @return An error if the option doesn't exist
+func (ap *Parser) SetMap(name string, duplicates DuplicatePolicy, keyValidators ...Validator) error {
+    op, found := ap.options[name]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: option %s does not exist", name))
+    }
+    op.Map = true
+    op.Duplicates = duplicates
+    op.KeyValidators = append(op.KeyValidators, keyValidators...)
+    if op.Metavar == "" {
+        op.Metavar = "KEY=VALUE"
+    }
+    ap.options[name] = op
+
+    return nil
+}
+
+// Stores a `key=value` pair given to a map option
+func (ap *Parser) setMapValue(results *Results, name string, pair string, origin ValueOrigin) error {
+    op := ap.options[name]
+    kv := strings.SplitN(pair, "=", 2)
+    if len(kv) != 2 || kv[0] == "" {
+        return errors.New(fmt.Sprintf("invalid value: %s -> %s (expected KEY=VALUE)", name, pair))
+    }
+    for _, v := range op.KeyValidators {
+        err := v.Check(kv[0])
+        if err != nil {
+            return errors.New(fmt.Sprintf("invalid value: %s -> %s (%s)", name, kv[0], err))
+        }
+    }
+    val, err := ap.normalizeOptionValue(name, kv[1])
+    if err != nil {
+        return err
+    }
+
+    // Values given on the command line replace the environment variable's
+    replace := origin.Source == SourceArgv && results.Source(name) != SourceArgv
+    if results.Map[name] == nil || replace {
+        results.Map[name] = map[string]string{}
+    }
+    _, duplicate := results.Map[name][kv[0]]
+    if duplicate && op.Duplicates == DuplicateError {
+        return errors.New(fmt.Sprintf("duplicate argument: %s %s", name, kv[0]))
+    }
+    results.Map[name][kv[0]] = val
+    results.Option[name] = kv[0] + "=" + val
+
+    return nil
+}
+
+/// Convert the values of a map option
+/// @param values values stored in `Results.Map`
+/// @param convert conversion function, for example `strconv.Atoi`
+/// @return The converted values or an error naming a key whose value couldn't
+/// be converted
+func ConvertMap[T any](values map[string]string, convert func(string) (T, error)) (map[string]T, error) {
+    converted := map[string]T{}
+    for k, v := range values {
+        c, err := convert(v)
+        if err != nil {
+            return nil, errors.New(fmt.Sprintf("invalid value: %s -> %s (%s)", k, v, err))
+        }
+        converted[k] = c
+    }
+
+    return converted, nil
+}
@@ -0,0 +1,49 @@
+package args
+
+import (
+    "os"
+    "strconv"
+    "strings"
+    "testing"
+)
+
+func TestMapOptions(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Option(Long("label"), KeyValue(DuplicateLastWins), ValidateKeys(RegexValidator("^[a-z]+$")))
+    parser.Option(Short('D'), KeyValue(DuplicateError), Env("APP_DEFINE"), Validate(RangeValidator(0, 10)))
+    os.Setenv("APP_DEFINE", "jobs=1")
+    defer os.Unsetenv("APP_DEFINE")
+
+    os.Args = []string{"app.exe", "--label", "env=prod", "--label=team=core", "--label", "env=dev"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    labels := results.Map["label"]
+    if len(labels) != 2 || labels["env"] != "dev" || labels["team"] != "core" { t.Error(labels) }
+    if results.Option["label"] != "env=dev" { t.Error(results.Option["label"]) }
+    if results.Map["D"]["jobs"] != "1" || results.Source("D") != SourceEnv { t.Error(results.Map["D"]) }
+
+    os.Args = []string{"app.exe", "-Djobs=4", "-D", "level=2"}
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    values, err := ConvertMap(results.Map["D"], strconv.Atoi)
+    if err != nil || len(values) != 2 || values["jobs"] != 4 || values["level"] != 2 { t.Error(values, err) }
+    _, err = ConvertMap(map[string]string{"a": "x"}, strconv.Atoi)
+    if err == nil || !strings.HasPrefix(err.Error(), "invalid value: a -> x") { t.Error(err) }
+
+    os.Args = []string{"app.exe", "-Da=1", "-Da=2"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "duplicate argument: D a" { t.Error(err) }
+    os.Args = []string{"app.exe", "-Da=11"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "invalid value: D -> 11 (not between 0 and 10)" { t.Error(err) }
+    os.Args = []string{"app.exe", "--label", "Env=prod"}
+    _, err = parser.Parse()
+    if err == nil || !strings.HasPrefix(err.(*ParseError).Message, "invalid value: label -> Env") { t.Error(err) }
+    os.Args = []string{"app.exe", "--label", "env"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "invalid value: label -> env (expected KEY=VALUE)" { t.Error(err) }
+
+    if parser.Usage() != "app [--label KEY=VALUE] [-D KEY=VALUE] [ARGS...]" { t.Error(parser.Usage()) }
+    if parser.SetMap("missing", DuplicateError) == nil { t.Error() }
+}
@@ -0,0 +1,365 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+)
+
+type argSpec struct {
+    long []string
+    short []string
+    help string
+    defaultsTo string
+    allowed []string
+    validators []Validator
+    choices []Choice
+    ignoreCase bool
+    hidden bool
+    deprecated bool
+    successor string
+    env string
+    required bool
+    metavar string
+    group string
+    persistent bool
+    defaultFunc func() (string, error)
+    defaultShownAs *string
+    keyValue bool
+    duplicates DuplicatePolicy
+    keyValidators []Validator
+    file bool
+    access FileAccess
+    secret bool
+}
+
+/// Configures a flag or option added with `Parser.Flag` or `Parser.Option`
+type ArgOption func(*argSpec)
+
+/// Set the long name of a flag or option. Subsequent long names are added as
+/// aliases
+/// @param name long name, used without the leading "--"
+func Long(name string) ArgOption {
+    return func(spec *argSpec) {
+        if name != "" { spec.long = append(spec.long, name) }
+    }
+}
+
+/// Add a single character short name to a flag or option. '\000' is ignored
+/// @param abbr short name, used without the leading "-"
+func Short(abbr rune) ArgOption {
+    return func(spec *argSpec) {
+        if abbr != '\000' { spec.short = append(spec.short, string(abbr)) }
+    }
+}
+
+/// Add a short name of one or more characters to a flag or option. Multi
+/// character short names can't be grouped with other short names
+/// @param abbr short name, used without the leading "-"
+func ShortName(abbr string) ArgOption {
+    return func(spec *argSpec) {
+        if abbr != "" { spec.short = append(spec.short, abbr) }
+    }
+}
+
+/// Set the description of a flag or option
+/// @param help description displayed by the `Help` function
+func Help(help string) ArgOption {
+    return func(spec *argSpec) {
+        spec.help = help
+    }
+}
+
+/// Set the default value of an option
+/// @param value default value
+func Default(value string) ArgOption {
+    return func(spec *argSpec) {
+        spec.defaultsTo = value
+    }
+}
+
+/// Compute the default value of an option with a function. It's only called
+/// if the option isn't given
+/// @param fn function returning the default value
+func DefaultFunc(fn func() (string, error)) ArgOption {
+    return func(spec *argSpec) {
+        spec.defaultFunc = fn
+    }
+}
+
+/// Set the text displayed instead of the default value of an option. An empty
+/// string hides the default value
+/// @param text displayed text
+func DefaultShownAs(text string) ArgOption {
+    return func(spec *argSpec) {
+        spec.defaultShownAs = &text
+    }
+}
+
+/// Restrict the values of an option. Doesn't necessarily need to contain the
+/// default value
+/// @param values allowed values
+func Allowed(values ...string) ArgOption {
+    return func(spec *argSpec) {
+        spec.allowed = append(spec.allowed, values...)
+    }
+}
+
+/// Add validators to an option
+/// @param validators validators to add
+func Validate(validators ...Validator) ArgOption {
+    return func(spec *argSpec) {
+        spec.validators = append(spec.validators, validators...)
+    }
+}
+
+/// Set the values accepted by an option
+/// @param choices accepted values
+func Choices(choices ...Choice) ArgOption {
+    return func(spec *argSpec) {
+        spec.choices = append(spec.choices, choices...)
+    }
+}
+
+/// Match an option's allowed values and choices regardless of case
+func IgnoreCase() ArgOption {
+    return func(spec *argSpec) {
+        spec.ignoreCase = true
+    }
+}
+
+/// Hide a flag or option from the `Help` function. It's still parsed
+func Hidden() ArgOption {
+    return func(spec *argSpec) {
+        spec.hidden = true
+    }
+}
+
+/// Accept a flag or option before and after any command. It's listed by the
+/// `Help` function under `GlobalOptionsHelpMsg`
+func Persistent() ArgOption {
+    return func(spec *argSpec) {
+        spec.persistent = true
+    }
+}
+
+/// Add a flag or option to a group created with `Parser.AddGroup`
+func Group(name string) ArgOption {
+    return func(spec *argSpec) {
+        spec.group = name
+    }
+}
+
+/// Mark a flag or option as deprecated. Using it emits a warning and, if
+/// `successor` isn't empty, stores its value under `successor`
+/// @param successor name of the flag or option replacing it. Can be empty
+func Deprecated(successor string) ArgOption {
+    return func(spec *argSpec) {
+        spec.deprecated = true
+        spec.successor = successor
+    }
+}
+
+/// Read a flag's or option's value from an environment variable when it isn't
+/// given on the command line
+/// @param variable environment variable's name
+func Env(variable string) ArgOption {
+    return func(spec *argSpec) {
+        spec.env = variable
+    }
+}
+
+/// Return an error from `Parse` if the option isn't given on the command line
+/// or through its environment variable
+func Required() ArgOption {
+    return func(spec *argSpec) {
+        spec.required = true
+    }
+}
+
+/// Set the name of an option's value displayed in the usage line and the
+/// `Help` function
+/// @param name value's name, for example "FILE"
+func Metavar(name string) ArgOption {
+    return func(spec *argSpec) {
+        spec.metavar = name
+    }
+}
+
+/// Make an option a map option. Each value is a `key=value` pair stored in
+/// `Results.Map`
+/// @param duplicates what to do when a key is given more than once
+func KeyValue(duplicates DuplicatePolicy) ArgOption {
+    return func(spec *argSpec) {
+        spec.keyValue = true
+        spec.duplicates = duplicates
+    }
+}
+
+/// Add validators checking the keys of a map option
+/// @param validators validators to add
+func ValidateKeys(validators ...Validator) ArgOption {
+    return func(spec *argSpec) {
+        spec.keyValidators = append(spec.keyValidators, validators...)
+    }
+}
+
+/// Make an option a file option. The file is checked by `Parse` and opened by
+/// `Results.File`
+/// @param access how the file is used
+func File(access FileAccess) ArgOption {
+    return func(spec *argSpec) {
+        spec.file = true
+        spec.access = access
+    }
+}
+
+/// Make an option a secret option. See `Parser.SetSecret`
+func Sensitive() ArgOption {
+    return func(spec *argSpec) {
+        spec.secret = true
+    }
+}
+
+// Applies the options and checks that none of the names are in use. Returns
+// the name used by `Results`
+func (ap *Parser) newArgSpec(opts []ArgOption) (*argSpec, string, error) {
+    spec := new(argSpec)
+    for _, opt := range opts {
+        opt(spec)
+    }
+
+    var name string
+    if len(spec.long) != 0 {
+        name = spec.long[0]
+    }else if len(spec.short) != 0 {
+        name = spec.short[0]
+    }else {
+        return nil, "", errors.New("invalid argument: missing name")
+    }
+    names := spec.long
+    if len(names) == 0 {
+        names = []string{name}
+    }
+    if spec.secret {
+        // Name of the companion option added by `SetSecret`
+        names = append(append([]string{}, names...), name + "-file")
+    }
+    seen := map[string]bool{}
+    for _, n := range names {
+        _, foundFl := ap.flags[n]
+        _, foundOp := ap.options[n]
+        _, foundAl := ap.aliases[n]
+        if foundFl || foundOp || foundAl || seen[n] {
+            return nil, "", errors.New(fmt.Sprintf("duplicate argument: %s", n))
+        }
+        seen[n] = true
+    }
+    seen = map[string]bool{}
+    for _, a := range spec.short {
+        _, foundFlAbr := ap.flagsAbbr[a]
+        _, foundOpAbr := ap.optionsAbbr[a]
+        if foundFlAbr || foundOpAbr || seen[a] {
+            return nil, "", errors.New(fmt.Sprintf("duplicate argument: %s", a))
+        }
+        seen[a] = true
+    }
+    if _, found := ap.groups[spec.group]; spec.group != "" && !found {
+        return nil, "", errors.New(fmt.Sprintf("invalid argument: group %s does not exist", spec.group))
+    }
+
+    return spec, name, nil
+}
+
+/// Add a flag
+/// @param opts flag's configuration. Requires at least a `Long` or `Short` name
+/// @return An error if the flag has no name or any of its names already exist
+func (ap *Parser) Flag(opts ...ArgOption) error {
+    spec, name, err := ap.newArgSpec(opts)
+    if err != nil {
+        return err
+    }
+    successor := ap.resolveAlias(spec.successor)
+    if _, found := ap.flags[successor]; spec.successor != "" && !found {
+        return errors.New(fmt.Sprintf("invalid argument: flag %s does not exist", spec.successor))
+    }
+    ap.flags[name] = boolFlag{
+        Help: spec.help, ShortOnly: len(spec.long) == 0, Hidden: spec.hidden,
+        Persistent: spec.persistent,
+    }
+    ap.flagsOrder = append(ap.flagsOrder, name)
+    for _, a := range spec.short {
+        ap.flagsAbbr[a] = name
+    }
+    for ii := 1; ii < len(spec.long); ii++ {
+        ap.aliases[spec.long[ii]] = name
+    }
+    if spec.deprecated {
+        ap.deprecated[name] = successor
+    }
+    if spec.env != "" {
+        ap.env[name] = spec.env
+    }
+    if spec.group != "" {
+        ap.addToGroup(name, spec.group)
+    }
+
+    return nil
+}
+
+/// Add an option
+/// @param opts option's configuration. Requires at least a `Long` or `Short`
+/// name
+/// @return An error if the option has no name or any of its names already
+/// exist
+func (ap *Parser) Option(opts ...ArgOption) error {
+    spec, name, err := ap.newArgSpec(opts)
+    if err != nil {
+        return err
+    }
+    successor := ap.resolveAlias(spec.successor)
+    if _, found := ap.options[successor]; spec.successor != "" && !found {
+        return errors.New(fmt.Sprintf("invalid argument: option %s does not exist", spec.successor))
+    }
+    ap.options[name] = option{
+        Help: spec.help,
+        ShortOnly: len(spec.long) == 0,
+        Hidden: spec.hidden,
+        DefaultsTo: spec.defaultsTo,
+        Allowed: spec.allowed,
+        Validators: spec.validators,
+        Choices: spec.choices,
+        IgnoreCase: spec.ignoreCase,
+        Required: spec.required,
+        Metavar: spec.metavar,
+        Persistent: spec.persistent,
+        DefaultFunc: spec.defaultFunc,
+        DefaultShownAs: spec.defaultShownAs,
+    }
+    if spec.keyValue {
+        ap.SetMap(name, spec.duplicates, spec.keyValidators...)
+    }
+    if spec.file {
+        ap.SetFile(name, spec.access)
+    }
+    ap.optionsOrder = append(ap.optionsOrder, name)
+    for _, a := range spec.short {
+        ap.optionsAbbr[a] = name
+    }
+    for ii := 1; ii < len(spec.long); ii++ {
+        ap.aliases[spec.long[ii]] = name
+    }
+    if spec.deprecated {
+        ap.deprecated[name] = successor
+    }
+    if spec.env != "" {
+        ap.env[name] = spec.env
+    }
+    if spec.group != "" {
+        ap.addToGroup(name, spec.group)
+    }
+    if spec.secret {
+        return ap.SetSecret(name)
+    }
+
+    return nil
+}
@@ -0,0 +1,43 @@
+package args
+
+import (
+    "os"
+    "testing"
+)
+
+func TestRegisterShortAndLongOnly(t *testing.T) {
+    os.Args = []string{"app.exe", "--verbose", "-q", "-nw", "-lvl=3", "--mode", "b", "-Z", "x"}
+    var parser Parser
+    parser.Init("Test", "")
+    if parser.Flag(Long("verbose")) != nil { t.Error() }
+    if parser.Flag(Long("debug")) != nil { t.Error() }
+    if parser.Flag(Short('q'), Help("Quiet")) != nil { t.Error() }
+    if parser.Flag(ShortName("nw")) != nil { t.Error() }
+    if parser.Option(ShortName("lvl"), Default("1")) != nil { t.Error() }
+    if parser.Option(Long("mode"), Long("md"), Default("a"), Allowed("a", "b")) != nil { t.Error() }
+    if parser.Option(Short('Z')) != nil { t.Error() }
+    if parser.Flag(Short('q')) == nil { t.Error() }
+    if parser.Flag(Long("md")) == nil { t.Error() }
+    if parser.Flag(Help("No name")) == nil { t.Error() }
+    results, err := parser.Parse()
+    if err != nil { t.Error(err) }
+    if !results.Flag["verbose"] { t.Error() }
+    if results.Flag["debug"] { t.Error() }
+    if !results.Flag["q"] { t.Error() }
+    if !results.Flag["nw"] { t.Error() }
+    if results.Option["lvl"] != "3" { t.Error() }
+    if results.Option["mode"] != "b" { t.Error() }
+    if results.Option["Z"] != "x" { t.Error() }
+
+    os.Args = []string{"app.exe", "--q"}
+    _, err = parser.Parse()
+    if err == nil { t.Error() }
+}
+
+func TestRegisterNoAbbr(t *testing.T) {
+    var parser Parser
+    parser.Init("Test", "")
+    if parser.AddFlag("flag01", "", '\000') != nil { t.Error() }
+    if parser.AddFlag("flag02", "", '\000') != nil { t.Error() }
+    if parser.AddOption("op01", "", '\000', "", []string{}) != nil { t.Error() }
+}
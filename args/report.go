@@ -0,0 +1,165 @@
+package args
+
+import (
+    "fmt"
+    "io"
+    "strings"
+)
+
+// Name of the flag or option typed as `token` or an empty string if it
+// doesn't exist
+func (ap *Parser) tokenArg(token string) string {
+    tokens := ap.tokenize(token)
+    if !tokens.Next() {
+        return ""
+    }
+
+    return ap.tokenName(tokens.Token())
+}
+
+// Name of the flag or option of a token or an empty string if it doesn't
+// exist
+func (ap *Parser) tokenName(t Token) string {
+    switch t.Kind {
+        case TokenLong, TokenLongValue:
+            name := ap.resolveAlias(t.Name)
+            if ap.isFlag(name) || ap.isOption(name) {
+                return name
+            }
+        case TokenShort:
+            fl, found := ap.flagsAbbr[t.Name]
+            if found { return fl }
+            op, found := ap.optionsAbbr[t.Name]
+            if found { return op }
+    }
+
+    return ""
+}
+
+// Tokenizer splitting `args` with the parser's syntax and short names
+func (ap *Parser) tokenize(args ...string) *Tokenizer {
+    tokens := NewTokenizer(args)
+    tokens.Syntax = ap.syntax()
+    tokens.IsShort = ap.isShortName
+
+    return tokens
+}
+
+func (ap *Parser) isShortName(name string) bool {
+    _, isFlag := ap.flagsAbbr[name]
+    _, isOption := ap.optionsAbbr[name]
+    return isFlag || isOption
+}
+
+func (ap *Parser) isFlag(name string) bool {
+    _, found := ap.flags[name]
+    return found
+}
+
+func (ap *Parser) isOption(name string) bool {
+    _, found := ap.options[name]
+    return found
+}
+
+// Long names of the visible flags and options, used to suggest a correction
+// for an unknown argument
+func (ap *Parser) longNames() []string {
+    names := []string{}
+    for _, k := range append(append([]string{}, ap.flagsOrder...), ap.optionsOrder...) {
+        if ap.isHidden(k) { continue }
+        names = append(names, ap.argLongNames(k)...)
+    }
+
+    return names
+}
+
+// Writes the names and first line of the help of a flag, option or
+// positional argument
+func (ap *Parser) writeArgHelp(w io.Writer, name string) {
+    var names string
+    var help string
+    color := ap.theme().Flag
+    if ap.isFlag(name) {
+        names = strings.Join(ap.argNames(name), ", ")
+        help = ap.flags[name].Help
+    }else if ap.isOption(name) {
+        names = ap.withMetavar(strings.Join(ap.argNames(name), ", "), ap.metavar(name))
+        help = ap.options[name].Help
+        color = ap.theme().Option
+    }else {
+        for _, p := range ap.positional {
+            if p.Name == name {
+                names = "<" + name + ">"
+                help = p.Help
+            }
+        }
+    }
+    if names == "" {
+        return
+    }
+    fmt.Fprint(w, "    ")
+    ap.colored(w, color, names)
+    help = strings.SplitN(strings.TrimSpace(help), "\n", 2)[0]
+    if help != "" {
+        fmt.Fprint(w, "  ")
+        ap.colored(w, ap.theme().OptionDescription, help)
+    }
+    fmt.Fprintln(w)
+}
+
+/// Write an error returned by `Parse` with the arguments that caused it, the
+/// help of the argument it's about, suggested corrections and the usage line
+/// @param w where the error is written
+/// @param err error returned by `Parse`
+func (ap *Parser) ReportError(w io.Writer, err error) {
+    if err == nil {
+        return
+    }
+    parseErr, isParseErr := err.(*ParseError)
+    if !isParseErr {
+        ap.colored(w, ap.theme().Error, "error:")
+        fmt.Fprintf(w, " %s\n", err.Error())
+        return
+    }
+
+    // Arguments are looked up in the command that returned the error
+    failed := ap
+    if parseErr.parser != nil { failed = parseErr.parser }
+    ap.colored(w, ap.theme().Error, "error:")
+    fmt.Fprintf(w, " %s\n", parseErr.Message)
+    if parseErr.Index >= 0 && parseErr.Index < len(parseErr.Args) {
+        prefix := "    "
+        if ap.name != "" { prefix += ap.name + " " }
+        fmt.Fprintf(w, "%s%s\n", prefix, strings.Join(parseErr.Args, " "))
+        offset := displayWidth(prefix)
+        for _, a := range parseErr.Args[:parseErr.Index] {
+            offset += displayWidth(a) + 1
+        }
+        token := parseErr.Args[parseErr.Index]
+        carets := displayWidth(token)
+        if carets == 0 { carets = 1 }
+        fmt.Fprint(w, strings.Repeat(" ", offset))
+        ap.colored(w, ap.theme().Error, strings.Repeat("^", carets))
+        fmt.Fprintln(w)
+
+        tokens := failed.tokenize(token)
+        tokens.Next()
+        kind := tokens.Token().Kind
+        if parseErr.Arg == "" && (kind == TokenLong || kind == TokenLongValue) {
+            typed := ap.syntax().longPrefix() + tokens.Token().Name
+            suggestions := suggest(typed, failed.longNames(), false)
+            if len(suggestions) != 0 {
+                fmt.Fprintf(w, "did you mean %s?\n", strings.Join(suggestions, " or "))
+            }
+        }
+    }
+    if parseErr.Arg != "" {
+        failed.writeArgHelp(w, parseErr.Arg)
+    }
+    if parseErr.Usage != "" {
+        fmt.Fprintf(w, "usage: %s\n", parseErr.Usage)
+    }
+    if ap.isFlag("help") && !ap.isHidden("help") {
+        fmt.Fprintf(w, "see '%s' for more information\n", strings.TrimSpace(ap.name + " " + ap.shortestName("help")))
+    }
+}
@@ -0,0 +1,95 @@
+package args
+
+import (
+    "bytes"
+    "errors"
+    "os"
+    "strings"
+    "testing"
+)
+
+func TestParseErrorIndex(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Option(Long("mode"), Choices(Choice{Value: "fast"}, Choice{Value: "slow"}))
+    parser.Option(Long("config"), Required())
+    os.Args = []string{"app.exe", "--config", "c", "--mode", "fsat"}
+    _, err := parser.Parse()
+    if err == nil { t.Fatal() }
+    parseErr := err.(*ParseError)
+    if parseErr.Index != 3 || parseErr.Arg != "mode" || len(parseErr.Args) != 4 { t.Error(parseErr) }
+    os.Args = []string{"app.exe", "--config", "c", "--mode=fsat"}
+    _, err = parser.Parse()
+    if err.(*ParseError).Index != 2 || err.(*ParseError).Arg != "mode" { t.Error(err) }
+    os.Args = []string{"app.exe"}
+    _, err = parser.Parse()
+    if err.(*ParseError).Index != -1 || err.(*ParseError).Arg != "config" { t.Error(err) }
+}
+
+func TestReportError(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Colors = ColorsNever
+    parser.Flag(Long("help"), Short('h'), Help("Display this message"))
+    parser.Flag(Long("verbose"), Help("Print every step"))
+    parser.Option(Long("mode"), Metavar("MODE"), Choices(Choice{Value: "fast"}, Choice{Value: "slow"}), Help("Speed"))
+    parser.Option(Long("config"), Required(), Help("Configuration file\nMore details"))
+    os.Args = []string{"app.exe", "--config", "c", "--mode", "fsat"}
+    _, err := parser.Parse()
+    var buf bytes.Buffer
+    parser.ReportError(&buf, err)
+    expected := "error: invalid value: mode -> fsat (did you mean fast?)\n" +
+        "    app --config c --mode fsat\n" +
+        "                          ^^^^\n" +
+        "    --mode MODE  Speed\n" +
+        "usage: app [-h] [--verbose] [--mode MODE] --config VALUE [ARGS...]\n" +
+        "see 'app -h' for more information\n"
+    if buf.String() != expected { t.Error(buf.String()) }
+
+    os.Args = []string{"app.exe", "--verbos"}
+    _, err = parser.Parse()
+    buf.Reset()
+    parser.ReportError(&buf, err)
+    expected = "error: invalid argument: verbos\n" +
+        "    app --verbos\n" +
+        "        ^^^^^^^^\n" +
+        "did you mean --verbose?\n" +
+        "usage: app [-h] [--verbose] [--mode MODE] --config VALUE [ARGS...]\n" +
+        "see 'app -h' for more information\n"
+    if buf.String() != expected { t.Error(buf.String()) }
+
+    os.Args = []string{"app.exe"}
+    _, err = parser.Parse()
+    buf.Reset()
+    parser.ReportError(&buf, err)
+    expected = "error: missing argument: --config\n" +
+        "    --config VALUE  Configuration file\n" +
+        "usage: app [-h] [--verbose] [--mode MODE] --config VALUE [ARGS...]\n" +
+        "see 'app -h' for more information\n"
+    if buf.String() != expected { t.Error(buf.String()) }
+
+    buf.Reset()
+    parser.Colors = ColorsAlways
+    parser.ReportError(&buf, errors.New("failed"))
+    if buf.String() != string(DefaultTheme.Error) + "error:\033[0m failed\n" { t.Error(buf.String()) }
+}
+
+func TestReportErrorCommand(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Colors = ColorsNever
+    parser.AddCommand("run", "")
+    run := parser.Command("run")
+    run.Option(Long("mode"), Choices(Choice{Value: "fast"}, Choice{Value: "slow"}), Help("Speed"))
+    os.Args = []string{"app.exe", "run", "--mode", "fsat"}
+    _, err := parser.Parse()
+    var buf bytes.Buffer
+    parser.ReportError(&buf, err)
+    if !strings.Contains(buf.String(), "    --mode VALUE  Speed\n") { t.Error(buf.String()) }
+
+    os.Args = []string{"app.exe", "run", "--modx"}
+    _, err = parser.Parse()
+    buf.Reset()
+    parser.ReportError(&buf, err)
+    if !strings.Contains(buf.String(), "did you mean --mode?\n") { t.Error(buf.String()) }
+}
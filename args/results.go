@@ -0,0 +1,108 @@
+package args
+
+type Source int
+
+const (
+    /// The value is the default value
+    SourceDefault Source = iota
+    /// The value was given on the command line
+    SourceArgv
+    /// The value was read from an environment variable
+    SourceEnv
+    /// The value was read from a configuration file
+    SourceConfig
+    /// The value was set by the program with `Results.SetFlag` or
+    /// `Results.SetOption`
+    SourceProgrammatic
+    /// The value was typed in answer to a prompt
+    SourcePrompt
+)
+
+func (s Source) String() string {
+    switch s {
+        case SourceArgv:
+            return "argv"
+        case SourceEnv:
+            return "env"
+        case SourceConfig:
+            return "config"
+        case SourceProgrammatic:
+            return "programmatic"
+        case SourcePrompt:
+            return "prompt"
+    }
+
+    return "default"
+}
+
+type ValueOrigin struct {
+    /// Where the value comes from
+    Source Source
+    /// Index of the argument the value was given in, without the program's
+    /// name, or -1 if it wasn't given on the command line
+    Index int
+    /// Argument the value was given in or the name of the environment
+    /// variable it was read from
+    Token string
+}
+
+// Origin of a value given on the command line at `args[i]`
+func argvOrigin(args []string, i int) ValueOrigin {
+    return ValueOrigin{Source: SourceArgv, Index: i, Token: args[i]}
+}
+
+/// Check if a flag or option was set instead of keeping its default value
+/// @param name flag's or option's name
+/// @return True if the value was given on the command line, in an environment
+/// variable, in a configuration file or by the program
+func (r *Results) IsSet(name string) bool {
+    return r.Source(name) != SourceDefault
+}
+
+/// Where the value of a flag or option comes from
+/// @param name flag's or option's name
+func (r *Results) Source(name string) Source {
+    return r.Origin(name).Source
+}
+
+/// Where the value of a flag or option comes from and the argument it was
+/// given in
+/// @param name flag's or option's name
+func (r *Results) Origin(name string) ValueOrigin {
+    origin, found := r.origins[name]
+    if !found {
+        return ValueOrigin{Source: SourceDefault, Index: -1}
+    }
+
+    return origin
+}
+
+/// Set the value of a flag, for example after reading a configuration file
+/// @param name flag's name
+/// @param value flag's value
+/// @param source where the value comes from
+func (r *Results) SetFlag(name string, value bool, source Source) {
+    r.Flag[name] = value
+    r.setOrigin(name, ValueOrigin{Source: source, Index: -1})
+}
+
+/// Set the value of an option, for example after reading a configuration
+/// file. The value isn't validated
+/// @param name option's name
+/// @param value option's value
+/// @param source where the value comes from
+func (r *Results) SetOption(name string, value string, source Source) {
+    r.Option[name] = value
+    r.setOrigin(name, ValueOrigin{Source: source, Index: -1})
+}
+
+func (r *Results) setOrigin(name string, origin ValueOrigin) {
+    if r.origins == nil {
+        r.origins = map[string]ValueOrigin{}
+    }
+    if origin.Source == SourceDefault {
+        delete(r.origins, name)
+        return
+    }
+    r.origins[name] = origin
+}
@@ -0,0 +1,39 @@
+package args
+
+import (
+    "os"
+    "testing"
+)
+
+func TestValueSources(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Flag(Long("verbose"), Short('v'))
+    parser.Flag(Long("quiet"), Short('q'))
+    parser.Flag(Long("color"), Env("ARGS_TEST_COLOR"))
+    parser.Option(Long("mode"), Short('m'), Default("fast"))
+    parser.Option(Long("level"), Default("1"))
+    parser.Option(Long("user"), Env("ARGS_TEST_USER"))
+    os.Setenv("ARGS_TEST_USER", "root")
+    os.Setenv("ARGS_TEST_COLOR", "true")
+    defer os.Unsetenv("ARGS_TEST_USER")
+    defer os.Unsetenv("ARGS_TEST_COLOR")
+
+    os.Args = []string{"app.exe", "in", "-vm", "fast", "--level=1"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if !results.IsSet("verbose") || results.IsSet("quiet") { t.Error() }
+    if !results.IsSet("mode") || results.Source("mode") != SourceArgv { t.Error() }
+    origin := results.Origin("mode")
+    if origin.Index != 1 || origin.Token != "-vm" { t.Error(origin) }
+    if results.Origin("level").Index != 3 || results.Origin("level").Token != "--level=1" { t.Error() }
+    if results.Origin("quiet").Source != SourceDefault || results.Origin("quiet").Index != -1 { t.Error() }
+    if results.Source("user") != SourceEnv || results.Origin("user").Token != "ARGS_TEST_USER" { t.Error() }
+    if results.Source("color") != SourceEnv || !results.Flag["color"] { t.Error() }
+
+    results.SetOption("level", "3", SourceConfig)
+    if results.Option["level"] != "3" || results.Source("level") != SourceConfig { t.Error() }
+    results.SetFlag("quiet", true, SourceProgrammatic)
+    if !results.Flag["quiet"] || !results.IsSet("quiet") { t.Error() }
+    if SourceProgrammatic.String() != "programmatic" || SourceDefault.String() != "default" { t.Error() }
+}
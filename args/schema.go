@@ -0,0 +1,341 @@
+package args
+
+import (
+    "encoding/json"
+)
+
+type CommandSchema struct {
+    Name string `json:"name"`
+    Help string `json:"help,omitempty"`
+    Aliases []string `json:"aliases,omitempty"`
+    DeprecatedAliases []string `json:"deprecatedAliases,omitempty"`
+    Hidden bool `json:"hidden,omitempty"`
+    Deprecated bool `json:"deprecated,omitempty"`
+    Successor string `json:"successor,omitempty"`
+    /// Definition of the command's own arguments, if it has any
+    Parser *Schema `json:"parser,omitempty"`
+}
+
+type FlagSchema struct {
+    Name string `json:"name"`
+    Help string `json:"help,omitempty"`
+    /// The flag has no long name, `Name` is its first short name
+    ShortOnly bool `json:"shortOnly,omitempty"`
+    Short []string `json:"short,omitempty"`
+    Aliases []string `json:"aliases,omitempty"`
+    DeprecatedAliases []string `json:"deprecatedAliases,omitempty"`
+    Hidden bool `json:"hidden,omitempty"`
+    Deprecated bool `json:"deprecated,omitempty"`
+    Successor string `json:"successor,omitempty"`
+    Env string `json:"env,omitempty"`
+    Persistent bool `json:"persistent,omitempty"`
+    /// The flag is set when it isn't given
+    DefaultTrue bool `json:"defaultTrue,omitempty"`
+    /// The flag accepts a boolean value in every syntax
+    TakesValue bool `json:"takesValue,omitempty"`
+}
+
+type OptionSchema struct {
+    FlagSchema
+    Default string `json:"default,omitempty"`
+    /// Text displayed instead of the default value, if set. Functions
+    /// computing the default value can't be restored from a schema
+    DefaultShownAs *string `json:"defaultShownAs,omitempty"`
+    Allowed []string `json:"allowed,omitempty"`
+    Choices []Choice `json:"choices,omitempty"`
+    IgnoreCase bool `json:"ignoreCase,omitempty"`
+    Required bool `json:"required,omitempty"`
+    Metavar string `json:"metavar,omitempty"`
+    Map bool `json:"map,omitempty"`
+    Duplicates DuplicatePolicy `json:"duplicates,omitempty"`
+    File bool `json:"file,omitempty"`
+    Access FileAccess `json:"access,omitempty"`
+    /// Secret options have no default value in a schema and their companion
+    /// options are left out
+    Secret bool `json:"secret,omitempty"`
+    /// Descriptions of the option's validators. Validators can't be restored
+    /// from a schema
+    Validators []string `json:"validators,omitempty"`
+}
+
+type PositionalSchema struct {
+    Name string `json:"name"`
+    Help string `json:"help,omitempty"`
+    Required bool `json:"required,omitempty"`
+    Variadic bool `json:"variadic,omitempty"`
+}
+
+type GroupSchema struct {
+    Name string `json:"name"`
+    Description string `json:"description,omitempty"`
+    Hidden bool `json:"hidden,omitempty"`
+    /// Names of the group's flags and options, in order
+    Args []string `json:"args,omitempty"`
+}
+
+type Schema struct {
+    Name string `json:"name"`
+    Description string `json:"description,omitempty"`
+    CommandRequired bool `json:"commandRequired,omitempty"`
+    Commands []CommandSchema `json:"commands,omitempty"`
+    Flags []FlagSchema `json:"flags,omitempty"`
+    Options []OptionSchema `json:"options,omitempty"`
+    Positionals []PositionalSchema `json:"positionals,omitempty"`
+    Groups []GroupSchema `json:"groups,omitempty"`
+}
+
+func (ap *Parser) flagSchema(name string, help string, shortOnly bool, hidden bool, abbr []string) FlagSchema {
+    fs := FlagSchema{
+        Name: name, Help: help, ShortOnly: shortOnly, Hidden: hidden, Short: abbr,
+        Env: ap.env[name], Persistent: ap.isPersistent(name),
+    }
+    for _, a := range invertAliases(ap.aliases)[name] {
+        _, dep := ap.deprecated[a]
+        if dep {
+            fs.DeprecatedAliases = append(fs.DeprecatedAliases, a)
+        }else {
+            fs.Aliases = append(fs.Aliases, a)
+        }
+    }
+    fs.Successor, fs.Deprecated = ap.deprecated[name]
+
+    return fs
+}
+
+/// Describe the parser's commands, flags, options and positional arguments
+/// @return The parser's definition
+func (ap *Parser) Schema() Schema {
+    schema := Schema{
+        Name: ap.name,
+        Description: ap.description,
+        CommandRequired: ap.CommandRequired,
+    }
+    commandAliases := invertAliases(ap.commandAliases)
+    for _, k := range ap.commandsOrder {
+        cmd := ap.commands[k]
+        cs := CommandSchema{Name: k, Help: cmd.Help, Hidden: cmd.Hidden}
+        for _, a := range commandAliases[k] {
+            _, dep := ap.deprecatedCommands[a]
+            if dep {
+                cs.DeprecatedAliases = append(cs.DeprecatedAliases, a)
+            }else {
+                cs.Aliases = append(cs.Aliases, a)
+            }
+        }
+        cs.Successor, cs.Deprecated = ap.deprecatedCommands[k]
+        if cmd.parser.hasOwnArgs() {
+            sub := cmd.parser.Schema()
+            cs.Parser = &sub
+        }
+        schema.Commands = append(schema.Commands, cs)
+    }
+    flagsAbbr := ap.getFlagsAbbr()
+    for _, k := range ap.flagsOrder {
+        fl := ap.flags[k]
+        flSchema := ap.flagSchema(k, fl.Help, fl.ShortOnly, fl.Hidden, flagsAbbr[k])
+        flSchema.DefaultTrue = fl.DefaultsTo
+        flSchema.TakesValue = fl.TakesValue
+        schema.Flags = append(schema.Flags, flSchema)
+    }
+    optionsAbbr := ap.getOptionsAbbr()
+    for _, k := range ap.optionsOrder {
+        op := ap.options[k]
+        if op.SecretOf != "" { continue }
+        opSchema := OptionSchema{
+            FlagSchema: ap.flagSchema(k, op.Help, op.ShortOnly, op.Hidden, optionsAbbr[k]),
+            Default: op.DefaultsTo,
+            DefaultShownAs: op.DefaultShownAs,
+            Allowed: op.Allowed,
+            Choices: op.Choices,
+            IgnoreCase: op.IgnoreCase,
+            Required: op.Required,
+            Metavar: op.Metavar,
+            Map: op.Map,
+            Duplicates: op.Duplicates,
+            File: op.File,
+            Access: op.Access,
+            Secret: op.Secret,
+        }
+        if op.Secret {
+            opSchema.Default = ""
+        }
+        for _, v := range op.Validators {
+            opSchema.Validators = append(opSchema.Validators, v.Description)
+        }
+        schema.Options = append(schema.Options, opSchema)
+    }
+    for _, p := range ap.positional {
+        schema.Positionals = append(schema.Positionals, PositionalSchema{
+            Name: p.Name, Help: p.Help, Required: p.Required, Variadic: p.Variadic,
+        })
+    }
+    for _, name := range ap.groupsOrder {
+        g := ap.groups[name]
+        schema.Groups = append(schema.Groups, GroupSchema{
+            Name: name, Description: g.Description, Hidden: g.Hidden, Args: append([]string{}, g.args...),
+        })
+    }
+
+    return schema
+}
+
+func (fs FlagSchema) argOptions() []ArgOption {
+    opts := []ArgOption{Help(fs.Help), Env(fs.Env)}
+    if !fs.ShortOnly {
+        opts = append(opts, Long(fs.Name))
+    }
+    for _, a := range fs.Aliases {
+        opts = append(opts, Long(a))
+    }
+    for _, a := range fs.DeprecatedAliases {
+        opts = append(opts, Long(a))
+    }
+    for _, a := range fs.Short {
+        opts = append(opts, ShortName(a))
+    }
+    if fs.Hidden {
+        opts = append(opts, Hidden())
+    }
+    if fs.Persistent {
+        opts = append(opts, Persistent())
+    }
+
+    return opts
+}
+
+/// Initialize the parser from a definition returned by `Schema`. Validators
+/// aren't restored
+/// @param schema parser's definition
+/// @return An error if the definition contains duplicate or invalid arguments
+func (ap *Parser) InitFromSchema(schema Schema) error {
+    ap.Init(schema.Name, schema.Description)
+    ap.CommandRequired = schema.CommandRequired
+    for _, gs := range schema.Groups {
+        err := ap.AddGroup(gs.Name, gs.Description)
+        if err != nil {
+            return err
+        }
+        if gs.Hidden {
+            ap.HideGroup(gs.Name)
+        }
+    }
+    for _, cs := range schema.Commands {
+        err := ap.AddCommand(cs.Name, cs.Help)
+        if err != nil {
+            return err
+        }
+        aliases := append([]string{}, cs.Aliases...)
+        err = ap.AddCommandAlias(cs.Name, append(aliases, cs.DeprecatedAliases...)...)
+        if err != nil {
+            return err
+        }
+        if cs.Hidden {
+            ap.HideCommand(cs.Name)
+        }
+        if cs.Parser != nil {
+            err = ap.Command(cs.Name).InitFromSchema(*cs.Parser)
+            if err != nil {
+                return err
+            }
+        }
+    }
+    for _, fs := range schema.Flags {
+        err := ap.Flag(fs.argOptions()...)
+        if err != nil {
+            return err
+        }
+        fl := ap.flags[fs.Name]
+        fl.DefaultsTo = fs.DefaultTrue
+        fl.TakesValue = fs.TakesValue
+        ap.flags[fs.Name] = fl
+    }
+    for _, opSchema := range schema.Options {
+        opts := append(
+            opSchema.argOptions(),
+            Default(opSchema.Default), Allowed(opSchema.Allowed...), Choices(opSchema.Choices...),
+        )
+        if opSchema.IgnoreCase {
+            opts = append(opts, IgnoreCase())
+        }
+        if opSchema.Required {
+            opts = append(opts, Required())
+        }
+        opts = append(opts, Metavar(opSchema.Metavar))
+        if opSchema.Map {
+            opts = append(opts, KeyValue(opSchema.Duplicates))
+        }
+        if opSchema.File {
+            opts = append(opts, File(opSchema.Access))
+        }
+        if opSchema.Secret {
+            opts = append(opts, Sensitive())
+        }
+        if opSchema.DefaultShownAs != nil {
+            opts = append(opts, DefaultShownAs(*opSchema.DefaultShownAs))
+        }
+        err := ap.Option(opts...)
+        if err != nil {
+            return err
+        }
+    }
+    for _, ps := range schema.Positionals {
+        err := ap.AddPositional(ps.Name, ps.Help, ps.Required, ps.Variadic)
+        if err != nil {
+            return err
+        }
+    }
+    for _, gs := range schema.Groups {
+        err := ap.SetGroup(gs.Name, gs.Args...)
+        if err != nil {
+            return err
+        }
+    }
+
+    // Successors may be defined after the arguments they replace
+    for _, cs := range schema.Commands {
+        for _, a := range cs.DeprecatedAliases {
+            ap.deprecatedCommands[a] = cs.Name
+        }
+        if cs.Deprecated {
+            err := ap.DeprecateCommand(cs.Name, cs.Successor)
+            if err != nil {
+                return err
+            }
+        }
+    }
+    args := []FlagSchema{}
+    args = append(args, schema.Flags...)
+    for _, opSchema := range schema.Options {
+        args = append(args, opSchema.FlagSchema)
+    }
+    for _, fs := range args {
+        for _, a := range fs.DeprecatedAliases {
+            ap.deprecated[a] = fs.Name
+        }
+        if fs.Deprecated {
+            err := ap.Deprecate(fs.Name, fs.Successor)
+            if err != nil {
+                return err
+            }
+        }
+    }
+
+    return nil
+}
+
+/// Encode the parser's definition returned by `Schema` as JSON
+func (ap *Parser) MarshalJSON() ([]byte, error) {
+    return json.Marshal(ap.Schema())
+}
+
+/// Initialize the parser from a JSON definition produced by `MarshalJSON`.
+/// Validators aren't restored
+func (ap *Parser) UnmarshalJSON(data []byte) error {
+    var schema Schema
+    err := json.Unmarshal(data, &schema)
+    if err != nil {
+        return err
+    }
+
+    return ap.InitFromSchema(schema)
+}
@@ -0,0 +1,63 @@
+package args
+
+import (
+    "bytes"
+    "encoding/json"
+    "os"
+    "reflect"
+    "testing"
+)
+
+func TestSchemaRoundTrip(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "Test program")
+    parser.AddCommand("run", "Run something")
+    parser.AddCommand("debug", "")
+    parser.HideCommand("debug")
+    parser.AddFlag("verbose", "Print more", 'v')
+    parser.AddFlag("secret", "", '\000')
+    parser.Hide("secret")
+    parser.AddOption("mode", "Mode to use", 'm', "fast", []string{"fast", "slow"})
+    parser.Option(Long("token"), Env("APP_TOKEN"))
+    parser.AddCommandAlias("run", "r")
+    parser.DeprecateCommand("start", "run")
+    parser.AddAlias("verbose", "verb")
+    parser.Deprecate("verbouse", "verbose")
+    parser.Flag(Short('q'), Help("Quiet"))
+    parser.SetChoices("mode", Choice{Value: "fast", Aliases: []string{"f"}, Help: "Go fast"})
+    parser.SetIgnoreCase("mode", true)
+    parser.AddValidator("mode", RegexValidator("^[a-z]+$"))
+    parser.AddPositional("input", "Input file", true, false)
+    parser.AddPositional("rest", "", false, true)
+
+    data, err := json.Marshal(&parser)
+    if err != nil { t.Fatal(err) }
+    var restored Parser
+    err = json.Unmarshal(data, &restored)
+    if err != nil { t.Fatal(err) }
+    schema := parser.Schema()
+    if !reflect.DeepEqual(restored.Schema().Commands, schema.Commands) { t.Error() }
+    if !reflect.DeepEqual(restored.Schema().Flags, schema.Flags) { t.Error() }
+    if !reflect.DeepEqual(restored.Schema().Positionals, schema.Positionals) { t.Error() }
+    if schema.Options[0].Validators[0] != "matches ^[a-z]+$" { t.Error() }
+    schema.Options[0].Validators = nil
+    if !reflect.DeepEqual(restored.Schema().Options, schema.Options) { t.Error() }
+    if schema.Flags[0].Short[0] != "v" || schema.Flags[0].Aliases[0] != "verb" { t.Error() }
+
+    var buf1, buf2 bytes.Buffer
+    parser.options["mode"] = restored.options["mode"]
+    parser.Markdown(&buf1)
+    restored.Markdown(&buf2)
+    if buf1.String() != buf2.String() { t.Error() }
+
+    os.Args = []string{"app.exe", "start", "--verbouse", "-q", "--mode", "F", "in"}
+    restored.OnWarning = func(string) {}
+    results, err := restored.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Command != "run" { t.Error() }
+    if !results.Flag["verbose"] || !results.Flag["q"] { t.Error() }
+    if results.Option["mode"] != "fast" { t.Error() }
+    os.Args = []string{"app.exe"}
+    _, err = restored.Parse()
+    if err == nil { t.Error() }
+}
@@ -0,0 +1,202 @@
+package args
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "io"
+    "os"
+    "strings"
+)
+
+// Text displayed instead of a secret
+const redacted = "******"
+
+/// Value of a secret option. Printing it shows `******` instead of the value
+type Secret string
+
+/// The secret
+func (s Secret) Value() string {
+    return string(s)
+}
+
+func (s Secret) String() string {
+    if s == "" {
+        return ""
+    }
+
+    return redacted
+}
+
+func (s Secret) GoString() string {
+    return fmt.Sprintf("%q", s.String())
+}
+
+func (s Secret) MarshalJSON() ([]byte, error) {
+    return json.Marshal(s.String())
+}
+
+/// Make an option a secret option. Its value is stored in `Results.Secret`
+/// and never displayed. The option also accepts `@-` to read the value from
+/// stdin and gets a companion option, named after it with a `-file` suffix,
+/// reading the value from a file. Required secrets that aren't given are
+/// asked for with `Prompt`
+/// @param name option's name
+/// @return An error if the option doesn't exist or the companion option's
+/// name is in use
+func (ap *Parser) SetSecret(name string) error {
+    op, found := ap.options[name]
+    if !found {
+        return errors.New(fmt.Sprintf("invalid argument: option %s does not exist", name))
+    }
+    file := name + "-file"
+    err := ap.Option(
+        Long(file), Help(fmt.Sprintf("Read %s from a file", ap.displayName(name))), File(FileRead),
+    )
+    if err != nil {
+        return err
+    }
+    companion := ap.options[file]
+    companion.SecretOf = name
+    companion.Hidden = op.Hidden
+    companion.Persistent = op.Persistent
+    ap.options[file] = companion
+    group, grouped := ap.argGroup[name]
+    if grouped {
+        ap.addToGroup(file, group)
+    }
+    op.Secret = true
+    ap.options[name] = op
+
+    return nil
+}
+
+// Reads a line without the line ending
+func readLine(r io.Reader) (string, error) {
+    var b strings.Builder
+    buf := make([]byte, 1)
+    for {
+        n, err := r.Read(buf)
+        if n == 1 && buf[0] == '\n' {
+            break
+        }
+        if n == 1 {
+            b.WriteByte(buf[0])
+        }
+        if err == io.EOF {
+            break
+        }
+        if err != nil {
+            return "", err
+        }
+    }
+
+    return strings.TrimSuffix(b.String(), "\r"), nil
+}
+
+// Removes the line ending of the last line of a secret read from a file
+func trimLineEnding(data []byte) string {
+    return strings.TrimSuffix(strings.TrimSuffix(string(data), "\n"), "\r")
+}
+
+// Copy of `args` with the last value read by `tokens` redacted
+func redactValue(args []string, tokens *Tokenizer) []string {
+    i := tokens.Index()
+    if tokens.value == "" || !strings.HasSuffix(args[i], tokens.value) {
+        return args
+    }
+    redactedArgs := append([]string{}, args...)
+    redactedArgs[i] = args[i][:len(args[i]) - len(tokens.value)] + redacted
+
+    return redactedArgs
+}
+
+// Stores the value of a secret option or of its companion option
+func (ap *Parser) setSecret(results *Results, name string, val string, origin ValueOrigin) error {
+    op := ap.options[name]
+    token := origin.Token
+    if op.SecretOf != "" {
+        path, err := ap.normalizeOptionValue(name, val)
+        if err != nil {
+            return err
+        }
+        results.Option[name] = path
+        results.setOrigin(name, origin)
+        var data []byte
+        if path == "-" {
+            data, err = io.ReadAll(os.Stdin)
+        }else {
+            data, err = os.ReadFile(path)
+        }
+        if err != nil {
+            return errors.New(fmt.Sprintf("invalid value: %s -> %s (%s)", name, path, err))
+        }
+        name = op.SecretOf
+        op = ap.options[name]
+        val = trimLineEnding(data)
+    }else if val == "@-" && origin.Source != SourcePrompt {
+        data, err := io.ReadAll(os.Stdin)
+        if err != nil {
+            return errors.New(fmt.Sprintf("invalid value: %s -> @- (%s)", name, err))
+        }
+        val = trimLineEnding(data)
+    }else if origin.Source == SourceArgv && strings.HasSuffix(token, val) {
+        token = token[:len(token) - len(val)] + redacted
+    }
+
+    for _, v := range op.Validators {
+        err := v.Check(val)
+        if err != nil {
+            return errors.New(fmt.Sprintf("invalid value: %s -> %s (%s)", name, redacted, err))
+        }
+    }
+    results.Secret[name] = Secret(val)
+    err := ap.setVar(name, val)
+    if err != nil {
+        return errors.New(fmt.Sprintf("invalid value: %s -> %s", name, redacted))
+    }
+    origin.Token = token
+    results.setOrigin(name, origin)
+    if origin.Source == SourceArgv {
+        results.Events = append(results.Events, Event{
+            Kind: EventOption, Name: name, Value: redacted, Index: origin.Index, Token: token,
+        })
+    }
+
+    return nil
+}
+
+// Asks on the terminal for a secret without echoing it
+func promptTerminal(name string) (string, error) {
+    fmt.Fprintf(os.Stderr, "%s: ", name)
+    val, err := readHidden(os.Stdin)
+    fmt.Fprintln(os.Stderr)
+
+    return val, err
+}
+
+// Asks for the required secrets that weren't given. Nothing is asked if
+// there's no `Prompt` and stdin isn't a terminal
+func (ap *Parser) promptSecrets(results *Results) error {
+    prompt := ap.root().Prompt
+    if prompt == nil {
+        if !isTerminal(os.Stdin) {
+            return nil
+        }
+        prompt = promptTerminal
+    }
+    for _, k := range ap.optionsOrder {
+        op := ap.options[k]
+        if !op.Secret || !op.Required || results.IsSet(k) { continue }
+        val, err := prompt(k)
+        if err != nil {
+            return errors.New(fmt.Sprintf("missing value: %s (%s)", ap.displayName(k), err))
+        }
+        err = ap.setSecret(results, k, val, ValueOrigin{Source: SourcePrompt, Index: -1})
+        if err != nil {
+            return err
+        }
+    }
+
+    return nil
+}
@@ -0,0 +1,100 @@
+package args
+
+import (
+    "encoding/json"
+    "errors"
+    "fmt"
+    "os"
+    "path/filepath"
+    "strings"
+    "testing"
+)
+
+func TestSecrets(t *testing.T) {
+    dir := t.TempDir()
+    path := filepath.Join(dir, "token")
+    os.WriteFile(path, []byte("from-file\n"), 0600)
+
+    var parser Parser
+    parser.Init("app", "")
+    parser.Colors = ColorsNever
+    parser.Option(Long("token"), Sensitive(), Default("hunter2"), Validate(RegexValidator("^[a-z-]+$")))
+    parser.Prompt = func(name string) (string, error) { return "", errors.New("no terminal") }
+
+    var b strings.Builder
+    parser.WriteHelp(&b)
+    if strings.Contains(b.String(), "hunter2") || !strings.Contains(b.String(), "--token-file FILE") { t.Error(b.String()) }
+
+    os.Args = []string{"app.exe", "--token=abc"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Secret["token"].Value() != "abc" { t.Error() }
+    if _, found := results.Option["token"]; found { t.Error() }
+    dump := fmt.Sprintf("%v %+v %#v", results, *results, results.Secret)
+    if strings.Contains(dump, "abc") { t.Error(dump) }
+    data, _ := json.Marshal(results)
+    if strings.Contains(string(data), "abc") { t.Error(string(data)) }
+    if results.Origin("token").Token != "--token=******" || results.Events[0].Value != "******" { t.Error(results.Events) }
+
+    os.Args = []string{"app.exe", "--token-file", path}
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Secret["token"].Value() != "from-file" || results.Source("token") != SourceArgv { t.Error(results.Secret) }
+
+    stdin := os.Stdin
+    defer func() { os.Stdin = stdin }()
+    os.Stdin, _ = os.Open(path)
+    os.Args = []string{"app.exe", "--token", "@-"}
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Secret["token"].Value() != "from-file" { t.Error(results.Secret) }
+
+    os.Args = []string{"app.exe"}
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Secret["token"].Value() != "hunter2" || results.Secret["token"].String() != "******" { t.Error() }
+
+    os.Args = []string{"app.exe", "--token", "Bad1"}
+    _, err = parser.Parse()
+    if err == nil || strings.Contains(err.Error(), "Bad1") { t.Error(err) }
+
+    parser.Option(Long("key"), Short('k'), Sensitive(), Required())
+    os.Args = []string{"app.exe"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "missing value: --key (no terminal)" { t.Error(err) }
+    parser.Prompt = func(name string) (string, error) { return name + "-typed", nil }
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Secret["key"].Value() != "key-typed" || results.Source("key") != SourcePrompt { t.Error() }
+    os.Args = []string{"app.exe", "-ksecret"}
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Origin("key").Token != "-k******" { t.Error(results.Origin("key")) }
+}
+
+func TestSecretReport(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Colors = ColorsNever
+    parser.Option(Long("token"), Short('t'), Sensitive(), Validate(RegexValidator("^[a-z]+$")))
+    parser.Flag(Long("verbose"), Short('v'))
+
+    os.Args = []string{"app.exe", "--token", "Hunter2"}
+    _, err := parser.Parse()
+    if err == nil { t.Fatal() }
+    var b strings.Builder
+    parser.ReportError(&b, err)
+    expected := "error: invalid value: token -> ****** (does not match ^[a-z]+$)\n" +
+        "    app --token ******\n" +
+        "                ^^^^^^\n"
+    if !strings.HasPrefix(b.String(), expected) || strings.Contains(b.String(), "Hunter2") { t.Error(b.String()) }
+    os.Args = []string{"app.exe", "--token=hunter", "-vtsecret", "--bogus"}
+    _, err = parser.Parse()
+    if err == nil { t.Fatal() }
+    args := strings.Join(err.(*ParseError).Args, " ")
+    if args != "--token=****** -vt****** --bogus" { t.Error(args) }
+
+    parser.Option(Long("key-file"))
+    if parser.Option(Long("key"), Sensitive()) == nil { t.Error() }
+    if parser.isOption("key") || len(parser.optionsOrder) != 3 { t.Error(parser.optionsOrder) }
+}
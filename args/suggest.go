@@ -0,0 +1,73 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+    "strings"
+)
+
+// Edit distance counting insertions, deletions, substitutions and
+// transpositions of adjacent characters
+func editDistance(a string, b string) int {
+    ra := []rune(a)
+    rb := []rune(b)
+    d := make([][]int, len(ra) + 1)
+    for i := range d {
+        d[i] = make([]int, len(rb) + 1)
+        d[i][0] = i
+    }
+    for j := range d[0] {
+        d[0][j] = j
+    }
+    for i := 1; i <= len(ra); i++ {
+        for j := 1; j <= len(rb); j++ {
+            cost := 1
+            if ra[i - 1] == rb[j - 1] { cost = 0 }
+            d[i][j] = d[i - 1][j] + 1
+            if d[i][j - 1] + 1 < d[i][j] { d[i][j] = d[i][j - 1] + 1 }
+            if d[i - 1][j - 1] + cost < d[i][j] { d[i][j] = d[i - 1][j - 1] + cost }
+            if i > 1 && j > 1 && ra[i - 1] == rb[j - 2] && ra[i - 2] == rb[j - 1] {
+                if d[i - 2][j - 2] + 1 < d[i][j] { d[i][j] = d[i - 2][j - 2] + 1 }
+            }
+        }
+    }
+
+    return d[len(ra)][len(rb)]
+}
+
+// Returns the candidates close enough to `val` to be a likely typo, closest
+// first
+func suggest(val string, candidates []string, ignoreCase bool) []string {
+    if ignoreCase { val = strings.ToLower(val) }
+    best := -1
+    suggestions := []string{}
+    for _, c := range candidates {
+        cmp := c
+        if ignoreCase { cmp = strings.ToLower(c) }
+        dist := editDistance(val, cmp)
+        maxDist := len([]rune(cmp)) / 3
+        if maxDist < 1 { maxDist = 1 }
+        if strings.HasPrefix(cmp, val) && val != "" { dist = 1 }
+        if dist > maxDist { continue }
+        if best == -1 || dist < best {
+            best = dist
+            suggestions = []string{c}
+        }else if dist == best {
+            suggestions = append(suggestions, c)
+        }
+    }
+
+    return suggestions
+}
+
+func invalidValueError(opt string, val string, candidates []string, ignoreCase bool) error {
+    suggestions := suggest(val, candidates, ignoreCase)
+    if len(suggestions) != 0 {
+        return errors.New(fmt.Sprintf(
+            "invalid value: %s -> %s (did you mean %s?)",
+            opt, val, strings.Join(suggestions, " or "),
+        ))
+    }
+
+    return errors.New(fmt.Sprintf("invalid value: %s -> %s", opt, val))
+}
@@ -0,0 +1,94 @@
+package args
+
+type Syntax int
+
+const (
+    /// `--name`, `--name=value`, `-n` and groups of short names such as
+    /// `-abc`
+    SyntaxGNU Syntax = iota
+    /// `-name`, `-name=value`, `-n`, as in Go's `flag` package. `--name` is
+    /// also accepted. Short names can't be grouped. Flags accept the values
+    /// accepted by `strconv.ParseBool`, such as `-v=false`
+    SyntaxSingleDash
+    /// `/name`, `/name:value`, `/n`. Values given as a separate argument can't
+    /// start with `/`
+    SyntaxWindows
+    /// `+name`, `+name=value`, `-n` and groups of short names such as `-abc`
+    SyntaxPlus
+)
+
+// Prefix of the long names
+func (s Syntax) longPrefix() string {
+    switch s {
+        case SyntaxSingleDash:
+            return "-"
+        case SyntaxWindows:
+            return "/"
+        case SyntaxPlus:
+            return "+"
+    }
+
+    return "--"
+}
+
+// Prefix of the short names
+func (s Syntax) shortPrefix() string {
+    if s == SyntaxWindows {
+        return "/"
+    }
+
+    return "-"
+}
+
+// Separates a name from the value given in the same argument
+func (s Syntax) separator() string {
+    if s == SyntaxWindows {
+        return ":"
+    }
+
+    return "="
+}
+
+// Reports whether `arg` is a flag or option instead of a value or positional
+// argument
+func (s Syntax) isOption(arg string) bool {
+    if len(arg) < 2 {
+        return false
+    }
+    switch s {
+        case SyntaxWindows:
+            return arg[0] == '/'
+        case SyntaxPlus:
+            return arg[0] == '-' || arg[0] == '+'
+    }
+
+    return arg[0] == '-'
+}
+
+// Syntax used by the parser and its commands
+func (ap *Parser) syntax() Syntax {
+    return ap.root().Syntax
+}
+
+// Long names and aliases of a flag or option as typed on the command line
+func (ap *Parser) argLongNames(name string) []string {
+    prefix := ap.syntax().longPrefix()
+    names := []string{}
+    if !ap.flags[name].ShortOnly && !ap.options[name].ShortOnly {
+        names = append(names, prefix + name)
+    }
+    for _, a := range ap.visibleAliases()[name] {
+        names = append(names, prefix + a)
+    }
+
+    return names
+}
+
+// Name of an option followed by its metavar
+func (ap *Parser) withMetavar(names string, metavar string) string {
+    if ap.syntax() == SyntaxWindows {
+        return names + ":" + metavar
+    }
+
+    return names + " " + metavar
+}
@@ -0,0 +1,95 @@
+package args
+
+import (
+    "os"
+    "strings"
+    "testing"
+)
+
+func TestSyntaxSingleDash(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Syntax = SyntaxSingleDash
+    parser.Flag(Long("verbose"), Short('v'))
+    parser.Flag(Long("all"), Short('a'))
+    parser.Option(Long("output"), Short('o'))
+    os.Args = []string{"app.exe", "-verbose", "-o", "x", "--all", "-output=y", "-"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if !results.Flag["verbose"] || !results.Flag["all"] || results.Option["output"] != "y" { t.Error() }
+    if len(results.Positional) != 1 || results.Positional[0] != "-" { t.Error() }
+    os.Args = []string{"app.exe", "-va"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "invalid argument: va" { t.Error(err) }
+    if parser.Usage() != "app [-v] [-a] [-o VALUE] [ARGS...]" { t.Error(parser.Usage()) }
+
+    os.Args = []string{"app.exe", "-v=false", "-all=true", "-output=x"}
+    results, err = parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if results.Flag["verbose"] || !results.Flag["all"] || !results.IsSet("verbose") { t.Error() }
+    os.Args = []string{"app.exe", "-verbose=false"}
+    results, err = parser.Parse()
+    if err != nil || results.Flag["verbose"] { t.Error(err) }
+    os.Args = []string{"app.exe", "-v=maybe"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "invalid value: v -> maybe (not a boolean)" { t.Error(err) }
+}
+
+func TestSyntaxFlagValue(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Flag(Long("verbose"), Short('v'))
+    os.Args = []string{"app.exe", "-v=true"}
+    _, err := parser.Parse()
+    if err == nil || err.(*ParseError).Message != "invalid value: v -> true (flags don't take a value)" { t.Error(err) }
+    os.Args = []string{"app.exe", "--verbose=false"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "invalid value: verbose -> false (flags don't take a value)" { t.Error(err) }
+}
+
+func TestSyntaxWindows(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Syntax = SyntaxWindows
+    parser.Flag(Long("verbose"), Short('v'))
+    parser.Flag(Long("all"), Short('a'))
+    parser.Option(Long("output"), Short('o'))
+    os.Args = []string{"app.exe", "/verbose", "/o:x", "/all", "-a", "/output", "y"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if !results.Flag["verbose"] || !results.Flag["all"] || results.Option["output"] != "y" { t.Error() }
+    if len(results.Positional) != 1 || results.Positional[0] != "-a" { t.Error() }
+    os.Args = []string{"app.exe", "/output", "/all"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "missing value: output" { t.Error(err) }
+    os.Args = []string{"app.exe", "/o:"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "missing value: o" { t.Error(err) }
+    if parser.Usage() != "app [/v] [/a] [/o:VALUE] [ARGS...]" { t.Error(parser.Usage()) }
+
+    parser.Colors = ColorsNever
+    var b strings.Builder
+    parser.WriteHelp(&b)
+    if !strings.Contains(b.String(), "/verbose, /v") || !strings.Contains(b.String(), "/output, /o") { t.Error(b.String()) }
+}
+
+func TestSyntaxPlus(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Syntax = SyntaxPlus
+    parser.Flag(Long("verbose"), Short('v'))
+    parser.Flag(Long("all"), Short('a'))
+    parser.Option(Long("output"), Short('o'))
+    os.Args = []string{"app.exe", "+verbose", "-ao", "x", "+output=y"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if !results.Flag["verbose"] || !results.Flag["all"] || results.Option["output"] != "y" { t.Error() }
+    os.Args = []string{"app.exe", "--verbose"}
+    _, err = parser.Parse()
+    if err == nil { t.Error() }
+    if strings.Join(parser.argNames("output"), ", ") != "+output, -o" { t.Error(parser.argNames("output")) }
+
+    var b strings.Builder
+    parser.ReportError(&b, &ParseError{Message: "invalid argument: verbos", Args: []string{"+verbos"}, Index: 0})
+    if !strings.Contains(b.String(), "did you mean +verbose?") { t.Error(b.String()) }
+}
@@ -0,0 +1,57 @@
+//go:build linux
+
+package args
+
+import (
+    "os"
+    "syscall"
+    "unsafe"
+)
+
+// Width of the terminal `file` is connected to. Returns 0 if it isn't a
+// terminal
+func ioctlWidth(file *os.File) int {
+    var size struct {
+        rows uint16
+        cols uint16
+        xpixel uint16
+        ypixel uint16
+    }
+    _, _, errno := syscall.Syscall(
+        syscall.SYS_IOCTL, file.Fd(), uintptr(syscall.TIOCGWINSZ), uintptr(unsafe.Pointer(&size)),
+    )
+    if errno != 0 {
+        return 0
+    }
+
+    return int(size.cols)
+}
+
+// Reports whether `file` is a terminal
+func isTerminal(file *os.File) bool {
+    var termios syscall.Termios
+    _, _, errno := syscall.Syscall(
+        syscall.SYS_IOCTL, file.Fd(), uintptr(syscall.TCGETS), uintptr(unsafe.Pointer(&termios)),
+    )
+
+    return errno == 0
+}
+
+// Reads a line from the terminal `file` is connected to without echoing it
+func readHidden(file *os.File) (string, error) {
+    var termios syscall.Termios
+    _, _, errno := syscall.Syscall(
+        syscall.SYS_IOCTL, file.Fd(), uintptr(syscall.TCGETS), uintptr(unsafe.Pointer(&termios)),
+    )
+    if errno != 0 {
+        return readLine(file)
+    }
+    hidden := termios
+    hidden.Lflag &^= syscall.ECHO
+    syscall.Syscall(syscall.SYS_IOCTL, file.Fd(), uintptr(syscall.TCSETS), uintptr(unsafe.Pointer(&hidden)))
+    defer syscall.Syscall(
+        syscall.SYS_IOCTL, file.Fd(), uintptr(syscall.TCSETS), uintptr(unsafe.Pointer(&termios)),
+    )
+
+    return readLine(file)
+}
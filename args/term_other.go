@@ -0,0 +1,29 @@
+//go:build !linux
+
+package args
+
+import (
+    "os"
+)
+
+// Width of the terminal `file` is connected to. Always 0 since the size of
+// the terminal can't be queried on this platform
+func ioctlWidth(file *os.File) int {
+    return 0
+}
+
+// Reports whether `file` is a terminal
+func isTerminal(file *os.File) bool {
+    info, err := file.Stat()
+    if err != nil {
+        return false
+    }
+
+    return info.Mode() & os.ModeCharDevice != 0
+}
+
+// Reads a line from `file`. The line is echoed since echoing can't be turned
+// off on this platform
+func readHidden(file *os.File) (string, error) {
+    return readLine(file)
+}
@@ -0,0 +1,167 @@
+package args
+
+import (
+    "fmt"
+    "io"
+    "os"
+)
+
+type ColorMode int
+
+const (
+    /// Never color the output
+    ColorsNever ColorMode = iota
+    /// Color the output if it's written to a terminal. `NO_COLOR` disables
+    /// colors and `FORCE_COLOR` enables them regardless of the output
+    ColorsAuto
+    /// Always color the output
+    ColorsAlways
+)
+
+type Theme struct {
+    /// Color of the title outputed by the `Help` function
+    Title ANSICode
+    /// Color of the description outputed by the `Help` function
+    Description ANSICode
+    /// Color of the headers outputed by the `Help` function
+    Header ANSICode
+    /// Color of the command names outputed by the `Help` function
+    Command ANSICode
+    /// Color of the command's description outputed by the `Help` function
+    CommandDescription ANSICode
+    /// Color of the flag names outputed by the `Help` function
+    Flag ANSICode
+    /// Color of the flag's description outputed by the `Help` function
+    FlagDescription ANSICode
+    /// Color of the option names outputed by the `Help` function
+    Option ANSICode
+    /// Color of the option's description outputed by the `Help` function
+    OptionDescription ANSICode
+    /// Color of the option's allowed values outputed by the `Help` function
+    OptionAllowed ANSICode
+    /// Color of the "error:" prefix of reported errors
+    Error ANSICode
+    /// Color of the "warning:" prefix of warnings
+    Warning ANSICode
+}
+
+/// Theme used by default
+var DefaultTheme = Theme{
+    Title: ANSIGreen,
+    Description: ANSIWhite,
+    Header: ANSIRed,
+    Command: ANSIMagenta,
+    CommandDescription: ANSIWhite,
+    Flag: ANSIBlue,
+    FlagDescription: ANSIWhite,
+    Option: ANSIBlue,
+    OptionDescription: ANSIWhite,
+    OptionAllowed: ANSIYellow,
+    Error: Style(ANSIBold, ANSIRed),
+    Warning: Style(ANSIBold, ANSIYellow),
+}
+
+/// Theme using only text styles, for terminals with unknown colors
+var MonochromeTheme = Theme{
+    Title: ANSIBold,
+    Header: Style(ANSIBold, ANSIUnderline),
+    Command: ANSIBold,
+    Flag: ANSIBold,
+    Option: ANSIBold,
+    OptionAllowed: ANSIUnderline,
+    Error: ANSIBold,
+    Warning: ANSIBold,
+}
+
+/// Theme using 256 colors
+var PastelTheme = Theme{
+    Title: Style(ANSIBold, Color256(114)),
+    Description: Color256(250),
+    Header: Style(ANSIBold, Color256(175)),
+    Command: Color256(183),
+    CommandDescription: Color256(250),
+    Flag: Color256(117),
+    FlagDescription: Color256(250),
+    Option: Color256(117),
+    OptionDescription: Color256(250),
+    OptionAllowed: Color256(222),
+    Error: Style(ANSIBold, Color256(210)),
+    Warning: Style(ANSIBold, Color256(222)),
+}
+
+/// Combine colors and styles
+/// @param codes colors and styles to combine
+/// @return The combined code
+func Style(codes ...ANSICode) ANSICode {
+    style := ANSICode("")
+    for _, c := range codes {
+        style += c
+    }
+
+    return style
+}
+
+/// Foreground color from the 256 color palette
+/// @param n color's index
+func Color256(n uint8) ANSICode {
+    return ANSICode(fmt.Sprintf("\033[38;5;%dm", n))
+}
+
+/// Background color from the 256 color palette
+/// @param n color's index
+func BGColor256(n uint8) ANSICode {
+    return ANSICode(fmt.Sprintf("\033[48;5;%dm", n))
+}
+
+/// Truecolor foreground color
+/// @param r red component
+/// @param g green component
+/// @param b blue component
+func RGB(r uint8, g uint8, b uint8) ANSICode {
+    return ANSICode(fmt.Sprintf("\033[38;2;%d;%d;%dm", r, g, b))
+}
+
+/// Truecolor background color
+/// @param r red component
+/// @param g green component
+/// @param b blue component
+func BGRGB(r uint8, g uint8, b uint8) ANSICode {
+    return ANSICode(fmt.Sprintf("\033[48;2;%d;%d;%dm", r, g, b))
+}
+
+// Theme used by the parser and its commands
+func (ap *Parser) theme() Theme {
+    return ap.root().Theme
+}
+
+// Reports whether the output written to `w` should be colored. Commands use
+// the color mode of the top level parser
+func (ap *Parser) useColors(w io.Writer) bool {
+    switch ap.root().Colors {
+        case ColorsAlways:
+            return true
+        case ColorsAuto:
+            if os.Getenv("NO_COLOR") != "" {
+                return false
+            }
+            force := os.Getenv("FORCE_COLOR")
+            if force != "" && force != "0" && force != "false" {
+                return true
+            }
+            file, isFile := w.(*os.File)
+            return isFile && isTerminal(file)
+    }
+
+    return false
+}
+
+// Writes `text` to `w` in the given color, if the output should be colored
+func (ap *Parser) colored(w io.Writer, color ANSICode, text string) {
+    if text == "" {
+        return
+    }
+    colors := color != "" && ap.useColors(w)
+    if colors { fmt.Fprint(w, color) }
+    fmt.Fprint(w, text)
+    if colors { fmt.Fprint(w, ANSIReset) }
+}
@@ -0,0 +1,57 @@
+package args
+
+import (
+    "bytes"
+    "os"
+    "strings"
+    "testing"
+)
+
+func TestColorCodes(t *testing.T) {
+    if Color256(208) != "\033[38;5;208m" { t.Error() }
+    if BGColor256(1) != "\033[48;5;1m" { t.Error() }
+    if RGB(255, 0, 10) != "\033[38;2;255;0;10m" { t.Error() }
+    if BGRGB(0, 0, 0) != "\033[48;2;0;0;0m" { t.Error() }
+    if Style(ANSIBold, ANSIRed) != "\033[1m\033[31m" { t.Error() }
+}
+
+func TestUseColors(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    var buf bytes.Buffer
+    os.Unsetenv("NO_COLOR")
+    os.Unsetenv("FORCE_COLOR")
+    if parser.useColors(&buf) { t.Error() }
+    os.Setenv("FORCE_COLOR", "1")
+    if !parser.useColors(&buf) { t.Error() }
+    os.Setenv("NO_COLOR", "1")
+    if parser.useColors(&buf) { t.Error() }
+    parser.Colors = ColorsAlways
+    if !parser.useColors(&buf) { t.Error() }
+    parser.Colors = ColorsNever
+    os.Unsetenv("NO_COLOR")
+    if parser.useColors(&buf) { t.Error() }
+    os.Unsetenv("FORCE_COLOR")
+}
+
+func TestThemeHelp(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Colors = ColorsAlways
+    parser.Theme = MonochromeTheme
+    parser.Flag(Long("verbose"), Help("Verbose"))
+    var buf bytes.Buffer
+    parser.WriteHelp(&buf)
+    if !strings.Contains(buf.String(), "\033[1m\033[4mFLAGS\033[0m") { t.Error(buf.String()) }
+    if !strings.Contains(buf.String(), "\033[1m--verbose\033[0m") { t.Error(buf.String()) }
+    if !strings.Contains(buf.String(), "        Verbose\n") { t.Error(buf.String()) }
+
+    buf.Reset()
+    parser.Warnings = &buf
+    parser.warn("old")
+    if buf.String() != "\033[1mwarning:\033[0m old\n" { t.Error(buf.String()) }
+    parser.Colors = ColorsNever
+    buf.Reset()
+    parser.warn("old")
+    if buf.String() != "warning: old\n" { t.Error(buf.String()) }
+}
@@ -0,0 +1,231 @@
+package args
+
+import (
+    "strings"
+)
+
+type TokenKind int
+
+const (
+    /// A short name from a group of short names such as `-abc`, or a short
+    /// name longer than one character accepted by `Tokenizer.IsShort`
+    TokenShort TokenKind = iota
+    /// A long name without a value, such as `--name`
+    TokenLong
+    /// A long name with a value, such as `--name=value`
+    TokenLongValue
+    /// A positional argument. Every argument following `--` is positional
+    TokenPositional
+    /// `--`
+    TokenTerminator
+)
+
+func (k TokenKind) String() string {
+    switch k {
+        case TokenShort:
+            return "short"
+        case TokenLong:
+            return "long"
+        case TokenLongValue:
+            return "long with value"
+        case TokenPositional:
+            return "positional"
+    }
+
+    return "terminator"
+}
+
+type Token struct {
+    Kind TokenKind
+    /// Name without its prefix
+    Name string
+    /// Value of a `TokenLongValue` or the positional argument
+    Value string
+    /// Index of the argument the token comes from
+    Index int
+    /// Argument the token comes from
+    Raw string
+}
+
+/// Splits command line arguments into tokens one at a time, like
+/// getopt_long. The values of options are read with `Value`
+type Tokenizer struct {
+    /// Reports whether a name longer than one character following a single
+    /// dash is a short name instead of a group of short names. Optional
+    IsShort func(name string) bool
+    /// Syntax of the flags and options
+    Syntax Syntax
+
+    args []string
+    // Index of the next argument and of the last argument consumed
+    next int
+    last int
+    token Token
+    // Text following the current short name in its argument and whether it
+    // holds more short names
+    rest string
+    cluster bool
+    valueRead bool
+    // Last value read by `Value`
+    value string
+    terminated bool
+}
+
+/// Create a tokenizer
+/// @param args arguments, without the program's name
+/// @return The tokenizer, positioned before the first token
+func NewTokenizer(args []string) *Tokenizer {
+    return &Tokenizer{args: args, last: -1}
+}
+
+/// Advance to the next token. The part of a group of short names left after
+/// reading a value with `Value` is skipped
+/// @return False if there are no tokens left
+func (t *Tokenizer) Next() bool {
+    t.valueRead = false
+    if t.rest != "" && t.cluster {
+        t.nextShort()
+        return true
+    }
+    t.rest = ""
+    if t.next >= len(t.args) {
+        return false
+    }
+
+    arg := t.args[t.next]
+    t.token = Token{Index: t.next, Raw: arg}
+    t.last = t.next
+    t.next++
+    if t.terminated {
+        t.token.Kind = TokenPositional
+        t.token.Value = arg
+    }else if arg == "--" {
+        t.token.Kind = TokenTerminator
+        t.terminated = true
+    }else if t.Syntax.isOption(arg) {
+        t.option(arg)
+    }else {
+        t.token.Kind = TokenPositional
+        t.token.Value = arg
+    }
+
+    return true
+}
+
+// Classifies a flag or option
+func (t *Tokenizer) option(arg string) {
+    sep := t.Syntax.separator()
+    name := arg[1:]
+    // Whether the argument can only be a long name and whether short names
+    // can be grouped
+    long := false
+    grouped := false
+    switch t.Syntax {
+        case SyntaxGNU:
+            long = strings.HasPrefix(arg, "--")
+            grouped = !long
+        case SyntaxSingleDash:
+            long = strings.HasPrefix(arg, "--")
+        case SyntaxPlus:
+            long = arg[0] == '+'
+            grouped = !long
+    }
+    if strings.HasPrefix(arg, "--") {
+        name = arg[2:]
+    }
+    key := strings.SplitN(name, sep, 2)[0]
+
+    if !long && (!grouped || len([]rune(key)) > 1) && t.IsShort != nil && t.IsShort(key) {
+        t.token.Kind = TokenShort
+        t.token.Name = key
+        t.rest = name[len(key):]
+        t.cluster = false
+    }else if grouped {
+        t.rest = name
+        t.cluster = true
+        t.nextShort()
+    }else if len(key) == len(name) {
+        t.token.Kind = TokenLong
+        t.token.Name = name
+    }else {
+        t.token.Kind = TokenLongValue
+        t.token.Name = key
+        t.token.Value = name[len(key) + len(sep):]
+    }
+}
+
+// Takes the next short name of the current group
+func (t *Tokenizer) nextShort() {
+    name := string([]rune(t.rest)[0])
+    t.rest = t.rest[len(name):]
+    t.token = Token{Kind: TokenShort, Name: name, Index: t.token.Index, Raw: t.token.Raw}
+}
+
+/// The current token
+func (t *Tokenizer) Token() Token {
+    return t.token
+}
+
+/// Check if the current token has a value attached: the value of a
+/// `TokenLongValue` or the text following a short name in its argument
+func (t *Tokenizer) HasValue() bool {
+    if t.valueRead {
+        return false
+    }
+
+    return t.token.Kind == TokenLongValue || (t.token.Kind == TokenShort && t.rest != "")
+}
+
+// Reports whether the text following the current short name starts with the
+// separator, as in `-v=false`
+func (t *Tokenizer) hasSeparator() bool {
+    return t.HasValue() && t.token.Kind == TokenShort && strings.HasPrefix(t.rest, t.Syntax.separator())
+}
+
+/// Read the value of the current option: the value attached to it, with a
+/// leading `=` (`:` for `SyntaxWindows`) removed for short names, or the next
+/// argument. The next argument isn't used if it's a flag or option. `-` is
+/// accepted, as it usually means stdin or stdout
+/// @return The value and false if there's no value or it's empty
+func (t *Tokenizer) Value() (string, bool) {
+    if t.valueRead {
+        return "", false
+    }
+    switch t.token.Kind {
+        case TokenLongValue:
+            t.valueRead = true
+            t.value = t.token.Value
+            return t.token.Value, t.token.Value != ""
+        case TokenShort:
+            if t.rest != "" {
+                val := strings.TrimPrefix(t.rest, t.Syntax.separator())
+                t.rest = ""
+                t.valueRead = true
+                t.value = val
+                return val, val != ""
+            }
+        case TokenLong:
+        default:
+            return "", false
+    }
+    if t.next >= len(t.args) || t.Syntax.isOption(t.args[t.next]) {
+        return "", false
+    }
+    t.valueRead = true
+    t.last = t.next
+    t.next++
+    t.value = t.args[t.last]
+
+    return t.args[t.last], true
+}
+
+/// Index of the last argument used by `Next` or `Value`
+func (t *Tokenizer) Index() int {
+    return t.last
+}
+
+/// Arguments that haven't been used yet. The rest of the current group of
+/// short names isn't included
+func (t *Tokenizer) Remaining() []string {
+    return t.args[t.next:]
+}
@@ -0,0 +1,80 @@
+package args
+
+import (
+    "os"
+    "testing"
+)
+
+func TestTokenizer(t *testing.T) {
+    tokens := NewTokenizer([]string{"-ab", "--name=x", "--out", "file", "-cv3", "-lvl=2", "-", "--", "-d"})
+    tokens.IsShort = func(name string) bool { return name == "lvl" }
+    expected := []Token{
+        {Kind: TokenShort, Name: "a", Index: 0, Raw: "-ab"},
+        {Kind: TokenShort, Name: "b", Index: 0, Raw: "-ab"},
+        {Kind: TokenLongValue, Name: "name", Value: "x", Index: 1, Raw: "--name=x"},
+        {Kind: TokenLong, Name: "out", Index: 2, Raw: "--out"},
+        {Kind: TokenShort, Name: "c", Index: 4, Raw: "-cv3"},
+        {Kind: TokenShort, Name: "v", Index: 4, Raw: "-cv3"},
+        {Kind: TokenShort, Name: "lvl", Index: 5, Raw: "-lvl=2"},
+        {Kind: TokenPositional, Value: "-", Index: 6, Raw: "-"},
+        {Kind: TokenTerminator, Index: 7, Raw: "--"},
+        {Kind: TokenPositional, Value: "-d", Index: 8, Raw: "-d"},
+    }
+    values := map[int]string{2: "x", 3: "file", 5: "3", 6: "2"}
+    for ii, e := range expected {
+        if !tokens.Next() { t.Fatal(ii) }
+        if tokens.Token() != e { t.Error(ii, tokens.Token()) }
+        val, isSet := values[ii]
+        if !isSet { continue }
+        v, found := tokens.Value()
+        if !found || v != val { t.Error(ii, v) }
+    }
+    if tokens.Next() { t.Error(tokens.Token()) }
+    if tokens.Index() != 8 || len(tokens.Remaining()) != 0 { t.Error() }
+    if TokenLongValue.String() != "long with value" { t.Error() }
+}
+
+func TestTokenizerValue(t *testing.T) {
+    tokens := NewTokenizer([]string{"--out", "-v", "-o=", "-ab", "c"})
+    tokens.Next()
+    _, found := tokens.Value()
+    if found || tokens.Index() != 0 { t.Error() }
+    tokens.Next()
+    if tokens.HasValue() { t.Error() }
+    tokens.Next()
+    if !tokens.HasValue() { t.Error() }
+    _, found = tokens.Value()
+    if found { t.Error() }
+    tokens.Next()
+    if !tokens.HasValue() || len(tokens.Remaining()) != 1 { t.Error() }
+    tokens.Next()
+    v, found := tokens.Value()
+    if !found || v != "c" || tokens.Index() != 4 { t.Error(v) }
+    if tokens.Next() { t.Error() }
+}
+
+func TestParseTokens(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Flag(Long("verbose"), Short('v'))
+    parser.Option(Long("output"), Short('o'))
+    parser.Option(Long("level"), ShortName("lvl"))
+    os.Args = []string{"app.exe", "-vo=a", "-lvl", "2", "x"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if !results.Flag["verbose"] || results.Option["output"] != "a" || results.Option["level"] != "2" { t.Error() }
+    if len(results.Positional) != 1 || results.Positional[0] != "x" { t.Error() }
+
+    os.Args = []string{"app.exe", "-vo="}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "missing value: o" { t.Error(err) }
+    os.Args = []string{"app.exe", "-lvl"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "missing value: -lvl" { t.Error(err) }
+    os.Args = []string{"app.exe", "--output="}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "missing value: output" { t.Error(err) }
+    os.Args = []string{"app.exe", "-x"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "invalid argument: x" { t.Error(err) }
+}
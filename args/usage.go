@@ -0,0 +1,119 @@
+package args
+
+import (
+    "strings"
+)
+
+type ParseError struct {
+    /// Description of the error
+    Message string
+    /// Usage line of the parser that returned the error
+    Usage string
+    /// Arguments that were parsed, without the program's name. Values of
+    /// secret options are replaced with `******`
+    Args []string
+    /// Index in `Args` of the argument that caused the error or -1 if the
+    /// error isn't caused by a single argument
+    Index int
+    /// Name of the flag, option or positional argument the error is about or
+    /// an empty string if it's unknown
+    Arg string
+    // Parser or command that returned the error, used to find `Arg` and
+    // suggestions in `ReportError`
+    parser *Parser
+}
+
+func (e *ParseError) Error() string {
+    if e.Usage == "" {
+        return e.Message
+    }
+
+    return e.Message + "\nusage: " + e.Usage
+}
+
+// Name of an option's value in the usage line
+func (ap *Parser) metavar(name string) string {
+    op := ap.options[name]
+    if op.Metavar != "" {
+        return op.Metavar
+    }
+
+    return "VALUE"
+}
+
+// Shortest way to type a flag or option
+func (ap *Parser) shortestName(name string) string {
+    names := ap.argNames(name)
+    shortest := names[0]
+    for _, n := range names[1:] {
+        if len(n) < len(shortest) { shortest = n }
+    }
+
+    return shortest
+}
+
+func (ap *Parser) usage() string {
+    parts := []string{}
+    if ap.name != "" {
+        parts = append(parts, ap.name)
+    }
+    for _, k := range ap.flagsOrder {
+        if ap.isHidden(k) { continue }
+        parts = append(parts, "[" + ap.shortestName(k) + "]")
+    }
+    for _, k := range ap.optionsOrder {
+        op := ap.options[k]
+        if ap.isHidden(k) { continue }
+        part := ap.withMetavar(ap.shortestName(k), ap.metavar(k))
+        if !op.Required {
+            part = "[" + part + "]"
+        }
+        parts = append(parts, part)
+    }
+    if len(ap.commands) != 0 {
+        if ap.CommandRequired {
+            parts = append(parts, "<command>")
+        }else {
+            parts = append(parts, "[<command>]")
+        }
+    }
+    if len(ap.positional) == 0 {
+        parts = append(parts, "[ARGS...]")
+    }
+    for _, p := range ap.positional {
+        part := "<" + p.Name + ">"
+        if p.Variadic {
+            part += "..."
+        }
+        if !p.Required {
+            part = "[" + part + "]"
+        }
+        parts = append(parts, part)
+    }
+
+    return strings.Join(parts, " ")
+}
+
+/// Generate the usage line, for example
+/// `app [-f] [-o VALUE] [<command>] [ARGS...]`. The usage line of a command
+/// without arguments of its own lists its parent's arguments
+/// @return The usage line
+func (ap *Parser) Usage() string {
+    if ap.parent != nil && !ap.hasOwnArgs() {
+        return ap.view().usage()
+    }
+
+    return ap.usage()
+}
+
+/// Generate the usage line of a command, as returned by the command's `Usage`
+/// @param name command's name
+/// @return The usage line or an empty string if the command doesn't exist
+func (ap *Parser) CommandUsage(name string) string {
+    cmd := ap.Command(name)
+    if cmd == nil {
+        return ""
+    }
+
+    return cmd.Usage()
+}
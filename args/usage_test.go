@@ -0,0 +1,50 @@
+package args
+
+import (
+    "os"
+    "testing"
+)
+
+func TestUsage(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.AddCommand("run", "")
+    parser.AddFlag("flag", "", 'f')
+    parser.AddFlag("verbose", "", '\000')
+    parser.AddOption("output", "", 'o', "", []string{})
+    parser.Option(Long("config"), Metavar("FILE"), Required())
+    parser.Option(Long("secret"), Hidden())
+    if parser.Usage() != "app [-f] [--verbose] [-o VALUE] --config FILE [<command>] [ARGS...]" {
+        t.Error(parser.Usage())
+    }
+    parser.CommandRequired = true
+    parser.AddPositional("input", "", true, false)
+    parser.AddPositional("files", "", false, true)
+    if parser.CommandUsage("run") != "app run [-f] [--verbose] [-o VALUE] --config FILE <input> [<files>...]" {
+        t.Error(parser.CommandUsage("run"))
+    }
+    if parser.CommandUsage("missing") != "" { t.Error() }
+    parser.AddCommand("build", "")
+    parser.Command("build").AddFlag("release", "", 'r')
+    if parser.CommandUsage("build") != "app build [-r] [ARGS...]" { t.Error(parser.CommandUsage("build")) }
+    if parser.CommandUsage("run") != parser.Command("run").Usage() { t.Error() }
+}
+
+func TestParseRequired(t *testing.T) {
+    var parser Parser
+    parser.Init("app", "")
+    parser.Option(Long("config"), Required(), Env("ARGS_TEST_CONFIG"))
+    os.Args = []string{"app.exe"}
+    _, err := parser.Parse()
+    if err == nil { t.Fatal() }
+    if err.Error() != "missing argument: --config\nusage: app --config VALUE [ARGS...]" { t.Error(err) }
+    if err.(*ParseError).Message != "missing argument: --config" { t.Error() }
+    os.Args = []string{"app.exe", "--config", "a"}
+    _, err = parser.Parse()
+    if err != nil { t.Error(err) }
+    os.Args = []string{"app.exe"}
+    os.Setenv("ARGS_TEST_CONFIG", "b")
+    defer os.Unsetenv("ARGS_TEST_CONFIG")
+    _, err = parser.Parse()
+    if err != nil { t.Error(err) }
+}
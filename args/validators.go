@@ -0,0 +1,142 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+    "net"
+    "net/url"
+    "os"
+    "regexp"
+    "strconv"
+)
+
+type Validator struct {
+    /// Short description displayed by the `Help` function next to the option
+    Description string
+    /// Return an error if the value isn't valid
+    Check func(string) error
+}
+
+/// Create a validator from a function
+/// @param description validator's description
+/// @param check function returning an error if the value isn't valid
+/// @return The validator
+func FuncValidator(description string, check func(string) error) Validator {
+    return Validator{Description: description, Check: check}
+}
+
+/// Create a validator that only accepts values matching a regular expression.
+/// Panics if the expression doesn't compile
+/// @param pattern regular expression
+/// @return The validator
+func RegexValidator(pattern string) Validator {
+    re := regexp.MustCompile(pattern)
+    return Validator{
+        Description: fmt.Sprintf("matches %s", pattern),
+        Check: func(val string) error {
+            if !re.MatchString(val) {
+                return errors.New(fmt.Sprintf("does not match %s", pattern))
+            }
+
+            return nil
+        },
+    }
+}
+
+/// Create a validator that only accepts numbers between `min` and `max`
+/// (inclusive)
+/// @param min minimum value
+/// @param max maximum value
+/// @return The validator
+func RangeValidator(min float64, max float64) Validator {
+    minStr := strconv.FormatFloat(min, 'g', -1, 64)
+    maxStr := strconv.FormatFloat(max, 'g', -1, 64)
+    return Validator{
+        Description: fmt.Sprintf("%s..%s", minStr, maxStr),
+        Check: func(val string) error {
+            num, err := strconv.ParseFloat(val, 64)
+            if err != nil {
+                return errors.New("not a number")
+            }
+            if num < min || num > max {
+                return errors.New(fmt.Sprintf("not between %s and %s", minStr, maxStr))
+            }
+
+            return nil
+        },
+    }
+}
+
+/// Create a validator that only accepts paths to existing files
+/// @return The validator
+func FileValidator() Validator {
+    return Validator{
+        Description: "existing file",
+        Check: func(val string) error {
+            info, err := os.Stat(val)
+            if err != nil {
+                return errors.New("file does not exist")
+            }
+            if info.IsDir() {
+                return errors.New("is a directory")
+            }
+
+            return nil
+        },
+    }
+}
+
+/// Create a validator that only accepts paths to existing directories
+/// @return The validator
+func DirValidator() Validator {
+    return Validator{
+        Description: "existing directory",
+        Check: func(val string) error {
+            info, err := os.Stat(val)
+            if err != nil {
+                return errors.New("directory does not exist")
+            }
+            if !info.IsDir() {
+                return errors.New("not a directory")
+            }
+
+            return nil
+        },
+    }
+}
+
+/// Create a validator that only accepts absolute URLs
+/// @return The validator
+func URLValidator() Validator {
+    return Validator{
+        Description: "URL",
+        Check: func(val string) error {
+            u, err := url.Parse(val)
+            if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+                return errors.New("not a valid URL")
+            }
+
+            return nil
+        },
+    }
+}
+
+/// Create a validator that only accepts `host:port` values
+/// @return The validator
+func HostPortValidator() Validator {
+    return Validator{
+        Description: "host:port",
+        Check: func(val string) error {
+            host, port, err := net.SplitHostPort(val)
+            if err != nil || host == "" {
+                return errors.New("not a valid host:port")
+            }
+            num, err := strconv.Atoi(port)
+            if err != nil || num < 0 || num > 65535 {
+                return errors.New(fmt.Sprintf("invalid port %s", port))
+            }
+
+            return nil
+        },
+    }
+}
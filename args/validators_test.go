@@ -0,0 +1,48 @@
+package args
+
+import (
+    "errors"
+    "os"
+    "testing"
+)
+
+func TestValidators(t *testing.T) {
+    os.Args = []string{"app.exe", "--port", "8080", "--name=abc", "-Hlocalhost:80"}
+    var parser Parser
+    parser.Init("Test", "")
+    parser.AddOption("port", "", '\000', "", []string{})
+    parser.AddOption("name", "", '\000', "", []string{})
+    parser.AddOption("host", "", 'H', "", []string{})
+    if parser.AddValidator("port", RangeValidator(1, 65535)) != nil { t.Error() }
+    if parser.AddValidator("name", RegexValidator("^[a-z]+$")) != nil { t.Error() }
+    if parser.AddValidator("host", HostPortValidator()) != nil { t.Error() }
+    if parser.AddValidator("missing", URLValidator()) == nil { t.Error() }
+    results, err := parser.Parse()
+    if err != nil { t.Error(err) }
+    if results.Option["port"] != "8080" { t.Error() }
+    if results.Option["name"] != "abc" { t.Error() }
+    if results.Option["host"] != "localhost:80" { t.Error() }
+
+    os.Args = []string{"app.exe", "--port", "70000"}
+    _, err = parser.Parse()
+    if err == nil { t.Error() }
+    os.Args = []string{"app.exe", "--name=ABC"}
+    _, err = parser.Parse()
+    if err == nil { t.Error() }
+    os.Args = []string{"app.exe", "-H", "localhost"}
+    _, err = parser.Parse()
+    if err == nil { t.Error() }
+}
+
+func TestFuncValidator(t *testing.T) {
+    even := FuncValidator("even length", func(val string) error {
+        if len(val) % 2 != 0 { return errors.New("odd length") }
+        return nil
+    })
+    if even.Check("ab") != nil { t.Error() }
+    if even.Check("abc") == nil { t.Error() }
+    if URLValidator().Check("https://example.com/a") != nil { t.Error() }
+    if URLValidator().Check("example.com") == nil { t.Error() }
+    if DirValidator().Check(os.TempDir()) != nil { t.Error() }
+    if FileValidator().Check(os.TempDir()) == nil { t.Error() }
+}
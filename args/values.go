@@ -0,0 +1,280 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+    "math/bits"
+    "net"
+    "net/url"
+    "os"
+    "regexp"
+    "strconv"
+    "strings"
+    "time"
+)
+
+// Multipliers of the units accepted by `ByteSizeValue`, by lowercase unit
+var byteUnits = map[string]uint64{
+    "": 1, "b": 1,
+    "k": 1000, "kb": 1000, "kib": 1 << 10,
+    "m": 1000 * 1000, "mb": 1000 * 1000, "mib": 1 << 20,
+    "g": 1000 * 1000 * 1000, "gb": 1000 * 1000 * 1000, "gib": 1 << 30,
+    "t": 1000 * 1000 * 1000 * 1000, "tb": 1000 * 1000 * 1000 * 1000, "tib": 1 << 40,
+}
+
+type byteSizeValue struct {
+    p *uint64
+}
+
+/// Value holding a number of bytes, such as `512`, `10MB` or `10MiB`. Units
+/// are case insensitive
+/// @param p where the number of bytes is stored
+func ByteSizeValue(p *uint64) Value {
+    return byteSizeValue{p}
+}
+
+func (v byteSizeValue) Set(val string) error {
+    digits := strings.TrimRightFunc(val, func(r rune) bool {
+        return (r < '0' || r > '9') && r != '.'
+    })
+    suffix := strings.TrimSpace(val[len(digits):])
+    unit, found := byteUnits[strings.ToLower(suffix)]
+    if !found {
+        return errors.New(fmt.Sprintf("unknown unit %s", suffix))
+    }
+    // The integer part is parsed exactly and only the fractional part goes
+    // through a float
+    intText, fracText, _ := strings.Cut(digits, ".")
+    if intText + fracText == "" || strings.Contains(fracText, ".") {
+        return errors.New("not a size")
+    }
+    var num uint64
+    if intText != "" {
+        var err error
+        num, err = strconv.ParseUint(intText, 10, 64)
+        if errors.Is(err, strconv.ErrRange) {
+            return errors.New("size too large")
+        }else if err != nil {
+            return errors.New("not a size")
+        }
+    }
+    frac, err := strconv.ParseFloat("0." + fracText, 64)
+    if err != nil {
+        return errors.New("not a size")
+    }
+    hi, size := bits.Mul64(num, unit)
+    size, carry := bits.Add64(size, uint64(frac * float64(unit)), 0)
+    if hi != 0 || carry != 0 {
+        return errors.New("size too large")
+    }
+    *v.p = size
+
+    return nil
+}
+
+func (v byteSizeValue) String() string {
+    units := []string{"TiB", "GiB", "MiB", "KiB"}
+    for _, u := range units {
+        size := byteUnits[strings.ToLower(u)]
+        if *v.p != 0 && *v.p % size == 0 {
+            return fmt.Sprintf("%d%s", *v.p / size, u)
+        }
+    }
+
+    return strconv.FormatUint(*v.p, 10)
+}
+
+func (v byteSizeValue) Type() string {
+    return "SIZE"
+}
+
+type ipValue struct {
+    p *net.IP
+}
+
+/// Value holding an IPv4 or IPv6 address
+/// @param p where the address is stored
+func IPValue(p *net.IP) Value {
+    return ipValue{p}
+}
+
+func (v ipValue) Set(val string) error {
+    ip := net.ParseIP(val)
+    if ip == nil {
+        return errors.New("not an IP address")
+    }
+    *v.p = ip
+
+    return nil
+}
+
+func (v ipValue) String() string {
+    if *v.p == nil {
+        return ""
+    }
+
+    return v.p.String()
+}
+
+func (v ipValue) Type() string {
+    return "IP"
+}
+
+type cidrValue struct {
+    p *net.IPNet
+}
+
+/// Value holding a network in CIDR notation, such as `192.168.0.0/16`
+/// @param p where the network is stored
+func CIDRValue(p *net.IPNet) Value {
+    return cidrValue{p}
+}
+
+func (v cidrValue) Set(val string) error {
+    _, network, err := net.ParseCIDR(val)
+    if err != nil {
+        return errors.New("not a CIDR network")
+    }
+    *v.p = *network
+
+    return nil
+}
+
+func (v cidrValue) String() string {
+    if v.p.IP == nil {
+        return ""
+    }
+
+    return v.p.String()
+}
+
+func (v cidrValue) Type() string {
+    return "CIDR"
+}
+
+type urlValue struct {
+    p *url.URL
+}
+
+/// Value holding an absolute URL
+/// @param p where the URL is stored
+func URLValue(p *url.URL) Value {
+    return urlValue{p}
+}
+
+func (v urlValue) Set(val string) error {
+    u, err := url.Parse(val)
+    if err != nil || u.Scheme == "" || (u.Host == "" && u.Opaque == "") {
+        return errors.New("not a valid URL")
+    }
+    *v.p = *u
+
+    return nil
+}
+
+func (v urlValue) String() string {
+    return v.p.String()
+}
+
+func (v urlValue) Type() string {
+    return "URL"
+}
+
+type regexpValue struct {
+    p **regexp.Regexp
+}
+
+/// Value holding a compiled regular expression
+/// @param p where the expression is stored
+func RegexpValue(p **regexp.Regexp) Value {
+    return regexpValue{p}
+}
+
+func (v regexpValue) Set(val string) error {
+    re, err := regexp.Compile(val)
+    if err != nil {
+        return errors.New("not a valid regular expression")
+    }
+    *v.p = re
+
+    return nil
+}
+
+func (v regexpValue) String() string {
+    if *v.p == nil {
+        return ""
+    }
+
+    return (*v.p).String()
+}
+
+func (v regexpValue) Type() string {
+    return "REGEX"
+}
+
+type timeValue struct {
+    p *time.Time
+    layout string
+}
+
+/// Value holding a time
+/// @param p where the time is stored
+/// @param layout format accepted, as used by `time.Parse`. `time.RFC3339` if
+/// empty
+func TimeValue(p *time.Time, layout string) Value {
+    if layout == "" {
+        layout = time.RFC3339
+    }
+
+    return timeValue{p, layout}
+}
+
+func (v timeValue) Set(val string) error {
+    t, err := time.Parse(v.layout, val)
+    if err != nil {
+        return errors.New(fmt.Sprintf("not a time in the format %s", v.layout))
+    }
+    *v.p = t
+
+    return nil
+}
+
+func (v timeValue) String() string {
+    if v.p.IsZero() {
+        return ""
+    }
+
+    return v.p.Format(v.layout)
+}
+
+func (v timeValue) Type() string {
+    return "TIME"
+}
+
+type fileModeValue struct {
+    p *os.FileMode
+}
+
+/// Value holding octal permission bits, such as `0755`
+/// @param p where the permissions are stored
+func FileModeValue(p *os.FileMode) Value {
+    return fileModeValue{p}
+}
+
+func (v fileModeValue) Set(val string) error {
+    mode, err := strconv.ParseUint(val, 8, 32)
+    if err != nil || mode > 0777 {
+        return errors.New("not an octal file mode")
+    }
+    *v.p = os.FileMode(mode)
+
+    return nil
+}
+
+func (v fileModeValue) String() string {
+    return fmt.Sprintf("%04o", uint32(v.p.Perm()))
+}
+
+func (v fileModeValue) Type() string {
+    return "MODE"
+}
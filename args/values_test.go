@@ -0,0 +1,80 @@
+package args
+
+import (
+    "net"
+    "net/url"
+    "os"
+    "regexp"
+    "strings"
+    "testing"
+    "time"
+)
+
+func TestValues(t *testing.T) {
+    var size uint64 = 1 << 20
+    var ip net.IP
+    var network net.IPNet
+    var u url.URL
+    var re *regexp.Regexp
+    var since time.Time
+    var mode os.FileMode = 0644
+    var parser Parser
+    parser.Init("app", "")
+    parser.Colors = ColorsNever
+    parser.Var(ByteSizeValue(&size), Long("limit"))
+    parser.Var(IPValue(&ip), Long("ip"))
+    parser.Var(CIDRValue(&network), Long("net"))
+    parser.Var(URLValue(&u), Long("url"))
+    parser.Var(RegexpValue(&re), Long("match"))
+    parser.Var(TimeValue(&since, ""), Long("since"))
+    parser.Var(FileModeValue(&mode), Long("mode"), Metavar("PERM"))
+
+    var b strings.Builder
+    parser.WriteHelp(&b)
+    help := b.String()
+    if !strings.Contains(help, "--limit SIZE") || !strings.Contains(help, "[default: 1MiB]") { t.Error(help) }
+    if !strings.Contains(help, "--net CIDR") || !strings.Contains(help, "--mode PERM") { t.Error(help) }
+    if !strings.Contains(help, "[default: 0644]") || strings.Contains(help, "--ip IP  [default") { t.Error(help) }
+
+    os.Args = []string{
+        "app.exe", "--limit", "10MiB", "--ip", "::1", "--net", "192.168.1.0/16", "--url", "https://example.com/x",
+        "--match", "^a+$", "--since", "2024-01-02T03:04:05Z", "--mode", "0755",
+    }
+    _, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    if size != 10 << 20 || !ip.Equal(net.IPv6loopback) || network.String() != "192.168.0.0/16" { t.Error(size, ip, network) }
+    if u.Host != "example.com" || !re.MatchString("aa") || since.Year() != 2024 || mode != 0755 { t.Error(u, since, mode) }
+
+    invalid := map[string]string{
+        "--limit=3 parsecs": "invalid value: limit -> 3 parsecs (unknown unit parsecs)",
+        "--limit=1.5kb": "",
+        "--limit=20000000TiB": "invalid value: limit -> 20000000TiB (size too large)",
+        "--ip=1.2.3": "invalid value: ip -> 1.2.3 (not an IP address)",
+        "--net=10.0.0.1": "invalid value: net -> 10.0.0.1 (not a CIDR network)",
+        "--url=example": "invalid value: url -> example (not a valid URL)",
+        "--match=(": "invalid value: match -> ( (not a valid regular expression)",
+        "--since=yesterday": "invalid value: since -> yesterday (not a time in the format " + time.RFC3339 + ")",
+        "--mode=0800": "invalid value: mode -> 0800 (not an octal file mode)",
+    }
+    for arg, msg := range invalid {
+        os.Args = []string{"app.exe", arg}
+        _, err = parser.Parse()
+        if msg == "" && err != nil { t.Error(err) }
+        if msg != "" && (err == nil || err.(*ParseError).Message != msg) { t.Error(arg, err) }
+    }
+    if size != 1500 { t.Error(size) }
+
+    exact := map[string]uint64{
+        "18446744073709551615": 18446744073709551615,
+        "9007199254740993": 9007199254740993,
+        "16777216TiB": 0,
+        ".5KiB": 512,
+        "0.1MB": 100000,
+    }
+    for arg, expected := range exact {
+        err = ByteSizeValue(&size).Set(arg)
+        if expected == 0 && (err == nil || err.Error() != "size too large") { t.Error(arg, err) }
+        if expected != 0 && (err != nil || size != expected) { t.Error(arg, size, err) }
+    }
+    if ByteSizeValue(&size).Set(".") == nil || ByteSizeValue(&size).Set("1.2.3") == nil { t.Error() }
+}
@@ -0,0 +1,109 @@
+package args
+
+import (
+    "errors"
+    "fmt"
+)
+
+/// Value of an option stored in a variable, like `flag.Value`. `Set` is
+/// called by `Parse` each time the option is given. Values with a
+/// `Type() string` method use its result as the option's metavar
+type Value interface {
+    /// Parse and store the value. The error is returned by `Parse`
+    Set(string) error
+    /// The value as typed on the command line
+    String() string
+}
+
+type stringValue struct {
+    p *string
+}
+
+func (v stringValue) Set(val string) error {
+    *v.p = val
+    return nil
+}
+
+func (v stringValue) String() string {
+    return *v.p
+}
+
+/// Add a flag stored in a variable. The variable is set to false when the
+/// flag is added and to the flag's value when it's given on the command line
+/// or in its environment variable
+/// @param p variable
+/// @param opts flag's configuration. Requires at least a `Long` or `Short` name
+/// @return An error if the flag has no name or any of its names already exist
+func (ap *Parser) FlagVar(p *bool, opts ...ArgOption) error {
+    err := ap.Flag(opts...)
+    if err != nil {
+        return err
+    }
+    name := ap.flagsOrder[len(ap.flagsOrder) - 1]
+    fl := ap.flags[name]
+    fl.Var = p
+    ap.flags[name] = fl
+    *p = false
+
+    return nil
+}
+
+/// Add an option stored in a variable. The variable's value is the default
+/// value, unless `Default` is given
+/// @param p variable
+/// @param opts option's configuration. Requires at least a `Long` or `Short`
+/// name
+/// @return An error if the option has no name or any of its names already
+/// exist
+func (ap *Parser) OptionVar(p *string, opts ...ArgOption) error {
+    return ap.Var(stringValue{p}, opts...)
+}
+
+/// Add an option stored in a `Value`. The value's `String` is the default
+/// value, unless `Default` is given
+/// @param v where the option's value is stored
+/// @param opts option's configuration. Requires at least a `Long` or `Short`
+/// name
+/// @return An error if the option has no name, any of its names already
+/// exist or `v` rejects the default value
+func (ap *Parser) Var(v Value, opts ...ArgOption) error {
+    opts = append([]ArgOption{Default(v.String())}, opts...)
+    // The default value is checked before the option is added
+    spec, name, err := ap.newArgSpec(opts)
+    if err != nil {
+        return err
+    }
+    if spec.defaultsTo != v.String() {
+        err = v.Set(spec.defaultsTo)
+        if err != nil {
+            return errors.New(fmt.Sprintf("invalid value: %s -> %s (%s)", name, spec.defaultsTo, err))
+        }
+    }
+    err = ap.Option(opts...)
+    if err != nil {
+        return err
+    }
+    op := ap.options[name]
+    op.Value = v
+    typed, hasType := v.(interface{ Type() string })
+    if op.Metavar == "" && hasType {
+        op.Metavar = typed.Type()
+    }
+    ap.options[name] = op
+
+    return nil
+}
+
+// Stores the value of an option in its variable, if it has one
+func (ap *Parser) setVar(name string, val string) error {
+    v := ap.options[name].Value
+    if v == nil {
+        return nil
+    }
+    err := v.Set(val)
+    if err != nil {
+        return errors.New(fmt.Sprintf("invalid value: %s -> %s (%s)", name, val, err))
+    }
+
+    return nil
+}
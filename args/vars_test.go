@@ -0,0 +1,71 @@
+package args
+
+import (
+    "errors"
+    "os"
+    "strconv"
+    "strings"
+    "testing"
+)
+
+type levelValue int
+
+func (l *levelValue) Set(val string) error {
+    switch strings.ToLower(val) {
+        case "low":
+            *l = 1
+        case "high":
+            *l = 2
+        default:
+            return errors.New("expected low or high")
+    }
+    return nil
+}
+
+func (l *levelValue) String() string {
+    return [...]string{"", "low", "high"}[*l]
+}
+
+type listValue []string
+
+func (l *listValue) Set(val string) error {
+    *l = append(*l, val)
+    return nil
+}
+
+func (l *listValue) String() string {
+    return strings.Join(*l, ",")
+}
+
+func TestVars(t *testing.T) {
+    verbose := true
+    output := "out.txt"
+    var level levelValue
+    var include listValue
+    var parser Parser
+    parser.Init("app", "")
+    parser.FlagVar(&verbose, Long("verbose"), Short('v'))
+    parser.OptionVar(&output, Long("output"), Short('o'))
+    parser.Var(&level, Long("level"), Default("low"))
+    parser.Var(&include, Long("include"), Short('I'))
+    parser.Option(Long("count"), DefaultFunc(func() (string, error) { return "7", nil }))
+    if verbose || level != 1 || parser.options["output"].DefaultsTo != "out.txt" { t.Error() }
+
+    os.Args = []string{"app.exe", "-v", "--level", "HIGH", "-Ia", "-I", "b"}
+    results, err := parser.Parse()
+    if err != nil { t.Fatal(err) }
+    count, _ := strconv.Atoi(results.Option["count"])
+    if !verbose || output != "out.txt" || level != 2 || count != 7 { t.Error(verbose, output, level) }
+    if include.String() != "a,b" || results.Option["include"] != "b" { t.Error(include) }
+
+    os.Args = []string{"app.exe", "-o", "x", "--level", "medium"}
+    _, err = parser.Parse()
+    if err == nil || err.(*ParseError).Message != "invalid value: level -> medium (expected low or high)" { t.Error(err) }
+    if err.(*ParseError).Index != 3 { t.Error(err.(*ParseError).Index) }
+    if output != "x" { t.Error(output) }
+
+    err = parser.Var(&level, Long("other"), Default("none"))
+    if err == nil || err.Error() != "invalid value: other -> none (expected low or high)" { t.Error(err) }
+    if parser.isOption("other") { t.Error() }
+    if parser.Var(&level, Long("other")) != nil { t.Error() }
+}